@@ -1,7 +1,9 @@
 package actorkit
 
 import (
+	"context"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +30,9 @@ var (
 
 	// ErrActorHasNoDiscoveryService is returned when actor has no discovery server.
 	ErrActorHasNoDiscoveryService = errors.New("Actor does not support discovery")
+
+	// ErrStashFull is returned when a suspended actor's stash has reached it's limit.
+	ErrStashFull = errors.New("Actor's stash is full")
 )
 
 //********************************************************
@@ -241,7 +246,17 @@ func UseMessageInvoker(st MessageInvoker) ActorOption {
 // ActorImpl
 //********************************************************
 
-var _ Actor = &ActorImpl{}
+var (
+	_ Actor       = &ActorImpl{}
+	_ Suspendable = &ActorImpl{}
+)
+
+// watchRequest is used to request the addition or removal of a death watch,
+// where done is closed once request is handled.
+type watchRequest struct {
+	addr Addr
+	done chan struct{}
+}
 
 type actorSub struct {
 	Actor Actor
@@ -288,7 +303,8 @@ type ActorImpl struct {
 	addActor            chan Actor
 	rmActor             chan Actor
 	signal              chan struct{}
-	sentinelChan        chan Addr
+	sentinelChan        chan watchRequest
+	unwatchChan         chan watchRequest
 	killChan            chan chan error
 	stopChan            chan chan error
 	destroyChan         chan chan error
@@ -299,6 +315,7 @@ type ActorImpl struct {
 
 	started     *SwitchImpl
 	starting    *SwitchImpl
+	restarting  *SwitchImpl
 	destruction *SwitchImpl
 	processable *SwitchImpl
 
@@ -316,8 +333,24 @@ type ActorImpl struct {
 	postDestroy PostDestroy
 
 	gsub         *es.Subscription
-	sentinelSubs map[Addr]Subscription
+	sentinelSubs map[string]Subscription
 	subs         map[Actor]Subscription
+
+	cl    sync.Mutex
+	cause interface{}
+
+	// suspended is accessed atomically, allowing reception to skip the
+	// stash lock while actor is not suspended, and only changed with sl held.
+	suspended  int32
+	sl         sync.Mutex
+	stashLimit int
+	stash      []stashedEnvelope
+}
+
+// stashedEnvelope holds an envelope received by a suspended actor.
+type stashedEnvelope struct {
+	addr Addr
+	env  Envelope
 }
 
 // NewActorImpl returns a new instance of an ActorImpl assigned giving protocol and service name.
@@ -403,7 +436,15 @@ func NewActorImpl(namespace string, protocol string, props Prop) *ActorImpl {
 		ac.postStop = nil
 	}
 
-	// add unbouned mailbox.
+	if props.Clock == nil {
+		props.Clock = SystemClock{}
+	}
+
+	// use mailbox factory if provided, else add unbouned mailbox.
+	if props.Mailbox == nil && props.Mailboxes != nil {
+		props.Mailbox = props.Mailboxes.NewMailbox(props.MailInvoker)
+	}
+
 	if props.Mailbox == nil {
 		props.Mailbox = UnboundedBoxQueue(props.MailInvoker)
 	}
@@ -431,7 +472,8 @@ func NewActorImpl(namespace string, protocol string, props Prop) *ActorImpl {
 	ac.addActor = make(chan Actor, 0)
 
 	ac.signal = make(chan struct{}, 1)
-	ac.sentinelChan = make(chan Addr, 1)
+	ac.sentinelChan = make(chan watchRequest, 1)
+	ac.unwatchChan = make(chan watchRequest, 1)
 	ac.stopChan = make(chan chan error, 1)
 	ac.killChan = make(chan chan error, 1)
 	ac.destroyChan = make(chan chan error, 1)
@@ -443,10 +485,12 @@ func NewActorImpl(namespace string, protocol string, props Prop) *ActorImpl {
 	ac.id = xid.New()
 	ac.started = NewSwitch()
 	ac.starting = NewSwitch()
+	ac.restarting = NewSwitch()
 	ac.destruction = NewSwitch()
 	ac.accessAddr = AccessOf(ac)
 	ac.processable = NewSwitch()
 	ac.subs = map[Actor]Subscription{}
+	ac.sentinelSubs = map[string]Subscription{}
 	ac.tree = NewActorTree(10)
 
 	ac.processable.On()
@@ -489,6 +533,11 @@ func (ati *ActorImpl) Mailbox() Mailbox {
 	return ati.props.Mailbox
 }
 
+// Clock returns actors underline clock.
+func (ati *ActorImpl) Clock() Clock {
+	return ati.props.Clock
+}
+
 // GetAddr returns the child of this actor which has this address string version.
 //
 // This method is more specific and will not respect or handle a address which
@@ -496,8 +545,17 @@ func (ati *ActorImpl) Mailbox() Mailbox {
 // the address tree till it finds the target actor or there is found no matching actor
 //
 func (ati *ActorImpl) GetAddr(addr string) (Addr, error) {
+	self := ati.Addr()
+	if addr == self {
+		return AccessOf(ati), nil
+	}
 
-	return nil, nil
+	if !strings.HasPrefix(addr, self+"/") {
+		return nil, errors.New("Address %q is not a descendant of actor %q", addr, self)
+	}
+
+	ids := strings.Split(strings.TrimPrefix(addr, self+"/"), "/")
+	return ati.GetChild(ids[0], ids[1:]...)
 }
 
 // GetChild returns the child of this actor which has this matching id.
@@ -526,19 +584,32 @@ func (ati *ActorImpl) Watch(fn func(interface{})) Subscription {
 	return ati.props.Event.Subscribe(fn, nil)
 }
 
-// DeathWatch asks this actor sentinel to advice on behaviour or operation to
-// be performed for the provided actor's states (i.e Stopped, Restarted, Killed, Destroyed).
-// being watched for.
+// DeathWatch watches provided address, delivering a Terminated message into this
+// actor's mailbox when the actor of address is stopped, killed or destroyed. Watching
+// an address already watched does nothing.
 //
-// If actor has no Sentinel then an error is returned.
-// Sentinels are required to advice on action for watched actors by watching actor.
+// If actor has a Sentinel, it will also be asked to advice on behaviour or operation
+// to be performed for the provided actor's states (i.e Stopped, Restarted, Killed, Destroyed).
 func (ati *ActorImpl) DeathWatch(addr Addr) error {
-	if ati.props.Sentinel == nil {
-		return errors.New("Actor does not have a sentinel")
+	return ati.requestWatch(ati.sentinelChan, addr)
+}
+
+// Unwatch ends the watch of provided address previously added through
+// ActorImpl.DeathWatch.
+func (ati *ActorImpl) Unwatch(addr Addr) error {
+	return ati.requestWatch(ati.unwatchChan, addr)
+}
+
+func (ati *ActorImpl) requestWatch(req chan watchRequest, addr Addr) error {
+	done := make(chan struct{})
+	select {
+	case req <- watchRequest{addr: addr, done: done}:
+	case <-time.After(ati.busyDur):
+		return errors.WrapOnly(ErrActorBusyState)
 	}
 
 	select {
-	case ati.sentinelChan <- addr:
+	case <-done:
 		return nil
 	case <-time.After(ati.busyDur):
 		return errors.WrapOnly(ErrActorBusyState)
@@ -552,8 +623,30 @@ func (ati *ActorImpl) Publish(message interface{}) {
 
 // Receive adds giving Envelope into actor's mailbox.
 func (ati *ActorImpl) Receive(a Addr, e Envelope) error {
+	if injector, ok := ati.props.MessageInvoker.(MessageInjector); ok {
+		e = injector.InjectRequest(a, e)
+	}
+
+	// sender of a recovered envelope delivers it to the dead letters if
+	// it's not accepted.
+	recovered := e.recovered
+	e.recovered = false
+
+	// if we are suspended, then stash till we run again.
+	if stashed, err := ati.stashEnvelope(a, e, recovered); stashed {
+		return err
+	}
+
 	// if we cant process, then return error.
 	if !ati.processable.IsOn() {
+		if !recovered {
+			ati.props.DeadLetters.RecoverMail(DeadMail{
+				To:      a,
+				Message: e,
+				Reason:  ActorStoppedReason,
+				Time:    ati.props.Clock.Now(),
+			})
+		}
 		return errors.New("actor is stopped and hence can't handle message")
 	}
 
@@ -563,7 +656,20 @@ func (ati *ActorImpl) Receive(a Addr, e Envelope) error {
 
 	ati.messages.Add(1)
 
-	return ati.props.Mailbox.Push(a, e)
+	if err := ati.props.Mailbox.Push(a, e); err != nil {
+		ati.messages.Done()
+
+		if !recovered {
+			ati.props.DeadLetters.RecoverMail(DeadMail{
+				To:      a,
+				Message: e,
+				Reason:  MailboxFullReason,
+				Time:    ati.props.Clock.Now(),
+			})
+		}
+		return err
+	}
+	return nil
 }
 
 // Discover returns actor's Addr from this actor's
@@ -630,6 +736,14 @@ func (ati *ActorImpl) Spawn(service string, prop Prop) (Addr, error) {
 		prop.DeadLetters = ati.props.DeadLetters
 	}
 
+	if prop.Mailboxes == nil {
+		prop.Mailboxes = ati.props.Mailboxes
+	}
+
+	if prop.Clock == nil {
+		prop.Clock = ati.props.Clock
+	}
+
 	am := NewActorImpl(ati.namespace, ati.protocol, prop)
 	am.parent = ati
 
@@ -703,6 +817,12 @@ func (ati *ActorImpl) Namespace() string {
 // to escalate to parent's supervisor or restart/stop or handle
 // giving actor as dictated by it's algorithm.
 func (ati *ActorImpl) Escalate(err interface{}, addr Addr) {
+	if addr == nil || addr.ID() == ati.ID() {
+		ati.cl.Lock()
+		ati.cause = err
+		ati.cl.Unlock()
+	}
+
 	go ati.props.Supervisor.Handle(err, addr, ati, ati.parent)
 }
 
@@ -741,6 +861,10 @@ func (ati *ActorImpl) Start() error {
 		return nil
 	}
 
+	if atomic.LoadInt32(&ati.suspended) == 1 {
+		defer ati.restarting.Off()
+	}
+
 	return ati.runSystem(false)
 }
 
@@ -762,9 +886,17 @@ func (ati *ActorImpl) RestartChildren() error {
 // mailbox. This will also restarts actors children.
 func (ati *ActorImpl) Restart() error {
 	if !ati.started.IsOn() {
+		// a suspended actor was stopped as part of it's restart.
+		if atomic.LoadInt32(&ati.suspended) == 1 {
+			defer ati.restarting.Off()
+			return ati.runSystem(true)
+		}
 		return ati.Start()
 	}
 
+	ati.restarting.On()
+	defer ati.restarting.Off()
+
 	if err := ati.Stop(); err != nil {
 		return err
 	}
@@ -775,6 +907,39 @@ func (ati *ActorImpl) Restart() error {
 	return nil
 }
 
+// Suspend stops the actor, where all messages received while it is not running
+// are stashed instead of being dead-lettered, to be replayed in order once the actor
+// is started or restarted. Messages received once the stash holds limit messages
+// are dead-lettered, where a limit not above zero allows an unbounded stash.
+//
+// A suspension is the start of a restart, hence death watchers are not notified
+// of the actor's stop, and a later call to ActorImpl.Restart runs a full restart.
+//
+// A killed or destroyed actor dead-letters all stashed messages.
+func (ati *ActorImpl) Suspend(limit int) error {
+	ati.sl.Lock()
+	ati.stashLimit = limit
+	atomic.StoreInt32(&ati.suspended, 1)
+	ati.sl.Unlock()
+
+	ati.restarting.On()
+	if err := ati.Stop(); err != nil {
+		ati.restarting.Off()
+		if ati.started.IsOn() {
+			ati.resumeReception()
+		}
+		return err
+	}
+	return nil
+}
+
+// Stashed returns the total messages stashed while actor is suspended.
+func (ati *ActorImpl) Stashed() int {
+	ati.sl.Lock()
+	defer ati.sl.Unlock()
+	return len(ati.stash)
+}
+
 // Stop stops the operations of the actor on processing received messages.
 // All pending messages will be kept, so the actor can continue once started.
 // To both stop and clear all messages, use ActorImpl.Kill().
@@ -817,6 +982,7 @@ func (ati *ActorImpl) StopChildren() error {
 // Kill immediately stops the actor and clears all pending messages.
 func (ati *ActorImpl) Kill() error {
 	if !ati.started.IsOn() {
+		ati.dropStash()
 		return nil
 	}
 
@@ -848,8 +1014,12 @@ func (ati *ActorImpl) KillChildren() error {
 
 // Destroy stops giving actor and emits a destruction event which
 // will remove giving actor from it's ancestry trees.
+//
+// An already stopped or killed actor is destroyed without being restarted.
 func (ati *ActorImpl) Destroy() error {
 	if !ati.started.IsOn() {
+		ati.dropStash()
+		ati.destroyTerminated()
 		return nil
 	}
 
@@ -897,8 +1067,12 @@ func (ati *ActorImpl) runSystem(restart bool) error {
 	ati.proc.Add(1)
 	ati.routines.Add(1)
 
+	ati.cl.Lock()
+	ati.cause = nil
+	ati.cl.Unlock()
+
 	ati.initRoutines()
-	ati.processable.On()
+	ati.resumeReception()
 
 	if restart {
 		atomic.AddInt64(&ati.restartedCount, 1)
@@ -945,6 +1119,10 @@ func (ati *ActorImpl) runSystem(restart bool) error {
 			Addr:   ati.accessAddr,
 			Signal: RESTARTED,
 		})
+
+		if ati.props.StateInvoker != nil {
+			ati.props.StateInvoker.InvokedRestarted(ati.accessAddr)
+		}
 	} else {
 		if ati.postStart != nil {
 			if err := ati.postStart.PostStart(ati.accessAddr); err != nil {
@@ -959,6 +1137,10 @@ func (ati *ActorImpl) runSystem(restart bool) error {
 			Addr:   ati.accessAddr,
 			Signal: RUNNING,
 		})
+
+		if ati.props.StateInvoker != nil {
+			ati.props.StateInvoker.InvokedStarted(ati.accessAddr)
+		}
 	}
 
 	ati.started.On()
@@ -966,17 +1148,108 @@ func (ati *ActorImpl) runSystem(restart bool) error {
 	return nil
 }
 
+// stashEnvelope stashes provided envelope if actor is suspended, returning
+// true if envelope was handled by the stash.
+func (ati *ActorImpl) stashEnvelope(a Addr, e Envelope, recovered bool) (bool, error) {
+	if atomic.LoadInt32(&ati.suspended) == 0 {
+		return false, nil
+	}
+
+	ati.sl.Lock()
+	defer ati.sl.Unlock()
+
+	// suspension may have ended while awaiting lock.
+	if atomic.LoadInt32(&ati.suspended) == 0 {
+		return false, nil
+	}
+
+	if ati.stashLimit > 0 && len(ati.stash) >= ati.stashLimit {
+		if !recovered {
+			ati.props.DeadLetters.RecoverMail(DeadMail{
+				To:      a,
+				Message: e,
+				Reason:  MailboxFullReason,
+				Time:    ati.props.Clock.Now(),
+			})
+		}
+		return true, errors.WrapOnly(ErrStashFull)
+	}
+
+	ati.stash = append(ati.stash, stashedEnvelope{addr: a, env: e})
+	return true, nil
+}
+
+// resumeReception turns on reception of messages, replaying all messages
+// stashed while actor was suspended before any new message is received.
+func (ati *ActorImpl) resumeReception() {
+	ati.sl.Lock()
+	defer ati.sl.Unlock()
+
+	ati.processable.On()
+
+	stash := ati.stash
+	ati.stash = nil
+
+	// suspension ends once stash is replayed, ensuring new messages are
+	// received after stashed ones.
+	defer atomic.StoreInt32(&ati.suspended, 0)
+
+	for _, item := range stash {
+		if ati.props.MessageInvoker != nil {
+			ati.props.MessageInvoker.InvokedRequest(item.addr, item.env)
+		}
+
+		ati.messages.Add(1)
+		if err := ati.props.Mailbox.Push(item.addr, item.env); err != nil {
+			ati.messages.Done()
+			ati.props.DeadLetters.RecoverMail(DeadMail{
+				To:      item.addr,
+				Message: item.env,
+				Reason:  MailboxFullReason,
+				Time:    ati.props.Clock.Now(),
+			})
+		}
+	}
+}
+
+// dropStash ends actor's suspension, dead-lettering all stashed messages.
+func (ati *ActorImpl) dropStash() {
+	ati.sl.Lock()
+	defer ati.sl.Unlock()
+
+	for _, item := range ati.stash {
+		ati.props.DeadLetters.RecoverMail(DeadMail{
+			To:      item.addr,
+			Message: item.env,
+			Reason:  ActorStoppedReason,
+			Time:    ati.props.Clock.Now(),
+		})
+	}
+
+	ati.stash = nil
+	if atomic.SwapInt32(&ati.suspended, 0) == 1 {
+		ati.restarting.Off()
+	}
+}
+
 func (ati *ActorImpl) initRoutines() {
 	waitTillRunned(ati.manageLifeCycle)
 	waitTillRunned(ati.readMessages)
 }
 
 func (ati *ActorImpl) exhaustMessages() {
+	ati.dropStash()
+
 	for !ati.props.Mailbox.IsEmpty() {
 		if nextAddr, next, err := ati.props.Mailbox.Pop(); err == nil {
 			ati.messages.Done()
 
-			dm := DeadMail{To: nextAddr, Message: next}
+			dm := DeadMail{
+				To:      nextAddr,
+				Message: next,
+				Reason:  ActorStoppedReason,
+				Time:    ati.props.Clock.Now(),
+			}
 			ati.props.DeadLetters.RecoverMail(dm)
 		}
 	}
@@ -1023,6 +1296,28 @@ func (ati *ActorImpl) postDestroySystem() {
 	ati.props.Signals.SignalState(ati.accessAddr, DESTROYED)
 	ati.destruction.Off()
 	ati.setState(DESTROYED)
+
+	if ati.props.StateInvoker != nil {
+		ati.props.StateInvoker.InvokedDestroyed(ati.accessAddr)
+	}
+}
+
+// destroyTerminated runs the destruction procedures for a stopped or killed
+// actor, which has no lifecycle routine to receive a destruction request, so
+// it's parent removes it from it's tree.
+func (ati *ActorImpl) destroyTerminated() {
+	switch ati.State() {
+	case STOPPED, KILLED:
+	default:
+		return
+	}
+
+	ati.death = ati.props.Clock.Now()
+	ati.preDestroySystem()
+	ati.preMidDestroySystem()
+	ati.destroyChildrenSystems()
+	ati.postDestroySystem()
+	ati.props.Event.Reset()
 }
 
 func (ati *ActorImpl) preKillSystem() {
@@ -1041,6 +1336,10 @@ func (ati *ActorImpl) postKillSystem() {
 		Addr:   ati.accessAddr,
 	})
 	ati.setState(KILLED)
+
+	if ati.props.StateInvoker != nil {
+		ati.props.StateInvoker.InvokedKilled(ati.accessAddr)
+	}
 }
 
 func (ati *ActorImpl) preStopSystem() {
@@ -1069,26 +1368,72 @@ func (ati *ActorImpl) postStopSystem() {
 
 	ati.started.Off()
 	ati.setState(STOPPED)
+
+	if ati.props.StateInvoker != nil {
+		ati.props.StateInvoker.InvokedStopped(ati.accessAddr)
+	}
 }
 
 func (ati *ActorImpl) addSentinelWatch(addr Addr) {
+	if _, ok := ati.sentinelSubs[addr.ID()]; ok {
+		return
+	}
+
 	sub := addr.Watch(func(ev interface{}) {
 		switch tm := ev.(type) {
 		case ActorSignal:
-			ati.props.Sentinel.Advice(addr, tm)
+			if ati.props.Sentinel != nil {
+				ati.props.Sentinel.Advice(addr, tm)
+			}
+		case Terminated:
+			tm.Addr = addr
+			if err := ati.Receive(ati.accessAddr, CreateEnvelope(addr, Header{}, tm)); err != nil {
+				ati.logger.Emit(ERROR, Message("Failed to deliver terminated message"))
+			}
 		default:
 			return
 		}
 	})
-	ati.sentinelSubs[addr] = sub
+	ati.sentinelSubs[addr.ID()] = sub
+}
+
+func (ati *ActorImpl) removeSentinelWatch(addr Addr) {
+	if sub, ok := ati.sentinelSubs[addr.ID()]; ok {
+		delete(ati.sentinelSubs, addr.ID())
+		sub.Stop()
+	}
 }
 
 func (ati *ActorImpl) stopSentinelSubscriptions() {
-	for _, sub := range ati.sentinelSubs {
+	for id, sub := range ati.sentinelSubs {
+		delete(ati.sentinelSubs, id)
 		sub.Stop()
 	}
 }
 
+// publishTerminated publishes a Terminated message for all watchers of actor,
+// it does nothing if actor is being stopped as part of a restart.
+func (ati *ActorImpl) publishTerminated(signal Signal) {
+	if signal == STOPPED && ati.restarting.IsOn() {
+		return
+	}
+
+	if signal != DESTROYED {
+		ati.death = ati.props.Clock.Now()
+	}
+
+	ati.cl.Lock()
+	cause := ati.cause
+	ati.cl.Unlock()
+
+	ati.props.Event.Publish(Terminated{
+		Addr:   ati.accessAddr,
+		Signal: signal,
+		Cause:  cause,
+		Stat:   ati.Stats(),
+	})
+}
+
 func (ati *ActorImpl) awaitMessageExhaustion() {
 	ati.processable.Off()
 	ati.messages.Wait()
@@ -1103,12 +1448,33 @@ func (ati *ActorImpl) stopMessageReception() {
 	// ati.mails.Signal() fails to signal end of giving mail check routine.
 	// hence am adding this into this as a temporary fix.
 	// Please remove once we figure how to fix this scheduling issue.
-	tm := time.AfterFunc(1*time.Second, func() {
-		ati.props.Mailbox.Signal()
-	})
+	//
+	// The signal is repeated till the routine ends, as a behaviour may
+	// still be processing a message when the first signal is sent.
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ati.props.Mailbox.Signal()
+			}
+		}
+	}()
 
 	ati.routines.Wait()
-	tm.Stop()
+	close(done)
+
+	// routine may have ended from a panic without receiving the
+	// signal, which must not end the routine of a later start.
+	select {
+	case <-ati.signal:
+	default:
+	}
 }
 
 func (ati *ActorImpl) startChildrenSystems() {
@@ -1155,17 +1521,19 @@ func (ati *ActorImpl) exhaustSignals() {
 	ati.exhaustSignalChan(ati.stopChan)
 	ati.exhaustSignalChan(ati.killChan)
 	ati.exhaustSentinel(ati.sentinelChan)
+	ati.exhaustSentinel(ati.unwatchChan)
 	ati.exhaustSignalChan(ati.destroyChan)
 	ati.exhaustSignalChan(ati.stopChildrenChan)
 	ati.exhaustSignalChan(ati.killChildrenChan)
 	ati.exhaustSignalChan(ati.destroyChildrenChan)
 }
 
-func (ati *ActorImpl) exhaustSentinel(signal chan Addr) {
+func (ati *ActorImpl) exhaustSentinel(signal chan watchRequest) {
 	if len(signal) == 0 {
 		return
 	}
-	<-signal
+	req := <-signal
+	close(req.done)
 }
 
 func (ati *ActorImpl) exhaustSignalChan(signal chan chan error) {
@@ -1188,7 +1556,19 @@ func (ati *ActorImpl) registerChild(ac Actor) {
 				return
 			}
 
-			ati.rmActor <- ac
+			// actor has no lifecycle routine to receive on rmActor
+			// once stopped or killed.
+			if !ati.started.IsOn() {
+				ati.tree.RemoveActor(ac)
+				return
+			}
+
+			// child may be destroyed while actor is stopping, which
+			// then no longer receives on rmActor.
+			select {
+			case ati.rmActor <- ac:
+			case <-time.After(ati.busyDur):
+			}
 		}
 	})
 
@@ -1215,9 +1595,16 @@ func (ati *ActorImpl) manageChild(an Actor) error {
 	// add new actor into tree.
 	ati.tree.AddActor(an)
 
-	// send actor for registration
-	ati.addActor <- an
-	return nil
+	// send actor for registration, which may never be received if actor
+	// is shutting down while spawning from it's own behaviour.
+	select {
+	case ati.addActor <- an:
+		return nil
+	case <-time.After(ati.busyDur):
+		ati.tree.RemoveActor(an)
+		an.Destroy()
+		return errors.WrapOnly(ErrActorBusyState)
+	}
 }
 
 func (ati *ActorImpl) manageLifeCycle() {
@@ -1234,10 +1621,16 @@ func (ati *ActorImpl) manageLifeCycle() {
 			// do nothing but also allow us avoid
 			// possible all goroutine sleep bug.
 			ati.logger.Emit(DEBUG, Message("Incurring deadlock safety skip"))
-		case addr := <-ati.sentinelChan:
+		case req := <-ati.sentinelChan:
 			ati.logger.Emit(DEBUG, Message("Initiating sentinel watch for addr"))
-			ati.addSentinelWatch(addr)
+			ati.addSentinelWatch(req.addr)
+			close(req.done)
 			ati.logger.Emit(DEBUG, Message("Done adding sentinel watch"))
+		case req := <-ati.unwatchChan:
+			ati.logger.Emit(DEBUG, Message("Removing sentinel watch for addr"))
+			ati.removeSentinelWatch(req.addr)
+			close(req.done)
+			ati.logger.Emit(DEBUG, Message("Done removing sentinel watch"))
 		case actor := <-ati.addActor:
 			ati.logger.Emit(DEBUG, Message("Initiating register for actor child"))
 			ati.registerChild(actor)
@@ -1259,6 +1652,8 @@ func (ati *ActorImpl) manageLifeCycle() {
 			ati.stopChildrenSystems()
 			ati.logger.Emit(DEBUG, Message("Running post-stop procedures"))
 			ati.postStopSystem()
+			ati.logger.Emit(DEBUG, Message("Publishing termination to watchers"))
+			ati.publishTerminated(STOPPED)
 			ati.logger.Emit(DEBUG, Message("Sending finished signal"))
 			res <- nil
 			ati.logger.Emit(DEBUG, Message("Done stopping"))
@@ -1288,6 +1683,8 @@ func (ati *ActorImpl) manageLifeCycle() {
 			ati.postStopSystem()
 			ati.logger.Emit(DEBUG, Message("Running post-kill procedure"))
 			ati.postKillSystem()
+			ati.logger.Emit(DEBUG, Message("Publishing termination to watchers"))
+			ati.publishTerminated(KILLED)
 			ati.logger.Emit(DEBUG, Message("Sending finished signal"))
 			res <- nil
 			ati.logger.Emit(DEBUG, Message("Done killing"))
@@ -1300,7 +1697,7 @@ func (ati *ActorImpl) manageLifeCycle() {
 			ati.logger.Emit(DEBUG, Message("Done killing children"))
 		case res := <-ati.destroyChan:
 			ati.logger.Emit(DEBUG, Message("Initiating destruction of actor"))
-			ati.death = time.Now()
+			ati.death = ati.props.Clock.Now()
 			ati.logger.Emit(DEBUG, Message("Running pre-destruction procedure"))
 			ati.preDestroySystem()
 			ati.logger.Emit(DEBUG, Message("Running pre-mid-destruction procedure"))
@@ -1319,6 +1716,8 @@ func (ati *ActorImpl) manageLifeCycle() {
 			ati.postStopSystem()
 			ati.logger.Emit(DEBUG, Message("Running post-destroy procedure"))
 			ati.postDestroySystem()
+			ati.logger.Emit(DEBUG, Message("Publishing termination to watchers"))
+			ati.publishTerminated(DESTROYED)
 			ati.logger.Emit(DEBUG, Message("Resetting event subscription queue"))
 			ati.props.Event.Reset()
 			ati.logger.Emit(DEBUG, Message("Sending finished signal"))
@@ -1356,6 +1755,11 @@ func (ati *ActorImpl) readMessages() {
 			}
 
 			ati.props.Event.Publish(event)
+
+			if ati.props.StateInvoker != nil {
+				ati.props.StateInvoker.InvokedPanic(ati.accessAddr, event)
+			}
+
 			ati.Escalate(event, ati.accessAddr)
 		}
 	}()
@@ -1391,15 +1795,35 @@ func (ati *ActorImpl) process(a Addr, x Envelope) {
 	// decrease message wait counter.
 	ati.messages.Done()
 
-	if ati.props.MessageInvoker != nil {
-		ati.props.MessageInvoker.InvokedProcessing(a, x)
+	// dead-letter envelopes whose deadline passed or context was cancelled.
+	if x.Expired(ati.props.Clock.Now()) {
+		ati.props.DeadLetters.RecoverMail(DeadMail{
+			To:      a,
+			Message: x,
+			Reason:  DeadlineExceededReason,
+			Time:    ati.props.Clock.Now(),
+		})
+		return
 	}
 
-	ati.props.Behaviour.Action(a, x)
+	// attach a context for envelopes with only a deadline header.
+	if _, derived := x.ctx.(derivedContext); x.ctx == nil || derived {
+		if deadline, ok := x.Deadline(); ok {
+			ctx, cancel := context.WithDeadline(context.Background(), deadline)
+			defer cancel()
+
+			x.ctx = derivedContext{Context: ctx}
+		}
+	}
 
 	if ati.props.MessageInvoker != nil {
-		ati.props.MessageInvoker.InvokedProcessed(a, x)
+		ati.props.MessageInvoker.InvokedProcessing(a, x)
+
+		// deferred to ensure the processed hook is called when behaviour panics.
+		defer ati.props.MessageInvoker.InvokedProcessed(a, x)
 	}
+
+	ati.props.Behaviour.Action(a, x)
 }
 
 //********************************************************
@@ -1504,12 +1928,17 @@ func (at *ActorTree) RemoveActor(c Actor) error {
 	at.ml.Lock()
 	defer at.ml.Unlock()
 
-	index := at.registry[c.ID()]
+	index, ok := at.registry[c.ID()]
+	if !ok {
+		return nil
+	}
+
 	total := len(at.children)
 
 	item := at.children[total-1]
 	if total == 1 {
 		delete(at.registry, c.ID())
+		delete(at.addresses, c.Addr())
 		at.children = nil
 		return nil
 	}
@@ -2,6 +2,7 @@ package actorkit_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/gokit/actorkit"
 	"github.com/stretchr/testify/require"
@@ -74,6 +75,34 @@ func TestActorChildMessaging(t *testing.T) {
 	require.Equal(t, content.Data, 2)
 }
 
+func TestActorGetAddr(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	child, err := system.Spawn("child", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+
+	grandchild, err := child.Spawn("grandchild", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+
+	root := system.Actor()
+
+	self, err := root.GetAddr(root.Addr())
+	require.NoError(t, err)
+	require.Equal(t, root.ID(), self.ID())
+
+	found, err := root.GetAddr(grandchild.Actor().Addr())
+	require.NoError(t, err)
+	require.Equal(t, grandchild.ID(), found.ID())
+
+	_, err = root.GetAddr(root.Addr() + "/unknown")
+	require.Error(t, err)
+
+	_, err = child.Actor().GetAddr(root.Addr())
+	require.Error(t, err)
+}
+
 func TestActorImplPanic(t *testing.T) {
 	supervisor := &actorkit.OneForOneSupervisor{
 		Max: 30,
@@ -159,3 +188,89 @@ func TestActorWithChildStates(t *testing.T) {
 	require.True(t, childAddr.ID() != am.ID())
 	require.Error(t, childAddr.Send("a", nil))
 }
+
+func TestActorImplStopsDuringSlowBehaviour(t *testing.T) {
+	started := make(chan struct{}, 1)
+	am := actorkit.FromFunc("ns", "slow", func(addr actorkit.Addr, envelope actorkit.Envelope) {
+		started <- struct{}{}
+		time.Sleep(1500 * time.Millisecond)
+	})
+
+	require.NoError(t, am.Start())
+	require.NoError(t, actorkit.AddressOf(am, "slow").Send(1, nil))
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- am.Stop()
+	}()
+
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("actor failed to stop after slow behaviour returned")
+	}
+	require.False(t, isRunning(am))
+}
+
+func TestActorImplDestroyWhileBehaviourSpawns(t *testing.T) {
+	gate := make(chan struct{})
+	started := make(chan struct{}, 1)
+	spawned := make(chan error, 1)
+
+	am := actorkit.FromFunc("ns", "spawner", func(addr actorkit.Addr, envelope actorkit.Envelope) {
+		started <- struct{}{}
+		<-gate
+
+		_, err := addr.Spawn("child", actorkit.Prop{Behaviour: &basic{}})
+		spawned <- err
+	})
+
+	require.NoError(t, am.Start())
+	require.NoError(t, actorkit.AddressOf(am, "spawner").Send(1, nil))
+	<-started
+
+	destroyed := make(chan error, 1)
+	go func() {
+		destroyed <- am.Destroy()
+	}()
+
+	// let destruction begin before behaviour spawns its child.
+	time.Sleep(200 * time.Millisecond)
+	close(gate)
+
+	select {
+	case err := <-destroyed:
+		require.NoError(t, err)
+	case <-time.After(20 * time.Second):
+		t.Fatal("actor failed to destroy while its behaviour was spawning")
+	}
+
+	require.Error(t, <-spawned)
+	require.Len(t, am.Children(), 0)
+}
+
+func TestActorDestroyKilledChild(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	child, err := system.Spawn("child", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+
+	grandchild, err := child.Spawn("grandchild", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+
+	require.NoError(t, child.Actor().Kill())
+	require.True(t, isKilled(child))
+	require.Len(t, system.Actor().Children(), 1)
+
+	require.NoError(t, child.Actor().Destroy())
+	require.Equal(t, actorkit.DESTROYED, child.State())
+	require.Equal(t, actorkit.DESTROYED, grandchild.State())
+
+	waitFor(t, func() bool {
+		return len(system.Actor().Children()) == 0
+	})
+}
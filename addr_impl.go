@@ -1,6 +1,7 @@
 package actorkit
 
 import (
+	"context"
 	"time"
 
 	"github.com/gokit/errors"
@@ -108,6 +109,9 @@ func (ad *ServiceSet) Remove(service string) bool {
 				ad.addrs[index] = ad.addrs[swap]
 				ad.addrs = ad.addrs[:swap]
 				delete(ad.set, addr.Addr())
+				if index < swap {
+					ad.set[ad.addrs[index].Addr()] = index
+				}
 				return true
 			}
 
@@ -128,6 +132,9 @@ func (ad *ServiceSet) RemoveAddr(addr Addr) bool {
 				ad.addrs[index] = ad.addrs[swap]
 				ad.addrs = ad.addrs[:swap]
 				delete(ad.set, addr.Addr())
+				if index < swap {
+					ad.set[ad.addrs[index].Addr()] = index
+				}
 				return true
 			}
 
@@ -354,6 +361,13 @@ func Restart(addr Addr) error {
 	return errors.WrapOnly(ErrHasNoActor)
 }
 
+// SendContext delivers provided data to giving address with provided context attached
+// to it's envelope, allowing the context's deadline and cancellation to be respected
+// by the receiving actor and propagated across further hops.
+func SendContext(ctx context.Context, addr Addr, data interface{}, sender Addr) error {
+	return addr.Forward(CreateEnvelope(sender, Header{}, data).WithContext(ctx))
+}
+
 // Poison stops the actor referenced by giving address, this also causes a restart of actor's children.
 func Poison(addr Addr) error {
 	if actor := addr.Actor(); actor != nil {
@@ -489,6 +503,8 @@ func (a *AddrImpl) Forward(e Envelope) error {
 		deadLetters.Publish(DeadMail{
 			To:      a,
 			Message: e,
+			Reason:  DeadLettersReason,
+			Time:    time.Now(),
 		})
 		return nil
 	}
@@ -501,6 +517,8 @@ func (a *AddrImpl) Send(data interface{}, sender Addr) error {
 		deadLetters.Publish(DeadMail{
 			To:      a,
 			Message: CreateEnvelope(sender, Header{}, data),
+			Reason:  DeadLettersReason,
+			Time:    time.Now(),
 		})
 		return nil
 	}
@@ -513,6 +531,8 @@ func (a *AddrImpl) SendWithHeader(data interface{}, h Header, sender Addr) error
 		deadLetters.Publish(DeadMail{
 			To:      a,
 			Message: CreateEnvelope(sender, h, data),
+			Reason:  DeadLettersReason,
+			Time:    time.Now(),
 		})
 		return nil
 	}
@@ -578,6 +598,14 @@ func (a *AddrImpl) DeathWatch(addr Addr) error {
 	return a.actor.DeathWatch(addr)
 }
 
+// Unwatch implements the DeathWatch interface.
+func (a *AddrImpl) Unwatch(addr Addr) error {
+	if a.deadletter {
+		return errors.WrapOnly(ErrHasNoActor)
+	}
+	return a.actor.Unwatch(addr)
+}
+
 // Watch adds  a giving function into the subscription
 // listeners of giving address events.
 func (a *AddrImpl) Watch(fn func(interface{})) Subscription {
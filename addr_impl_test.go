@@ -44,3 +44,34 @@ func TestDeadLetterAddr(t *testing.T) {
 	require.Len(t, signal, 1)
 	<-signal
 }
+
+func TestServiceSetRemoveKeepsIndexes(t *testing.T) {
+	system, err := actorkit.Ancestor("kitkat", "localhost:0", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	a := actorkit.AddressOf(system.Actor(), "a")
+	b := actorkit.AddressOf(system.Actor(), "b")
+	c := actorkit.AddressOf(system.Actor(), "c")
+
+	set := actorkit.NewServiceSet()
+	require.True(t, set.Add(a))
+	require.True(t, set.Add(b))
+	require.True(t, set.Add(c))
+
+	require.True(t, set.Remove(a.Addr()))
+	require.False(t, set.Has(a.Addr()))
+	require.Equal(t, 0, set.IndexOf(c))
+
+	found, ok := set.Get(c.Addr())
+	require.True(t, ok)
+	require.Equal(t, c.Addr(), found.Addr())
+
+	require.True(t, set.RemoveAddr(b))
+	require.Equal(t, 0, set.IndexOf(c))
+	require.Len(t, set.Set(), 1)
+
+	require.True(t, set.RemoveAddr(c))
+	require.Len(t, set.Set(), 0)
+	require.Equal(t, -1, set.IndexOf(c))
+}
@@ -0,0 +1,346 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gokit/errors"
+
+	"github.com/gokit/actorkit"
+)
+
+// actions supported by the Control handler.
+const (
+	StopAction            = "stop"
+	KillAction            = "kill"
+	RestartAction         = "restart"
+	DestroyAction         = "destroy"
+	StopChildrenAction    = "stop-children"
+	KillChildrenAction    = "kill-children"
+	RestartChildrenAction = "restart-children"
+	DestroyChildrenAction = "destroy-children"
+	SendAction            = "send"
+)
+
+var (
+	// ErrUnauthorized is returned when a control request has no valid bearer token.
+	ErrUnauthorized = errors.New("request is not authorized")
+
+	// ErrUnknownAction is returned when a control request targets an unknown action.
+	ErrUnknownAction = errors.New("unknown control action")
+)
+
+// AuditEvent is published into the event stream of the Control's Root actor, or it's
+// Events if set, for every control request, regardless of it's success, including
+// requests rejected for their method or token.
+type AuditEvent struct {
+	Action string
+	Addr   string
+	Remote string
+	Time   time.Time
+	Err    error
+}
+
+// SendRequest defines the JSON body of a send action.
+type SendRequest struct {
+	Header actorkit.Header `json:"header"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// controlResponse defines the JSON body returned for a control request.
+type controlResponse struct {
+	Action string `json:"action"`
+	Addr   string `json:"addr"`
+	State  string `json:"state,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Control implements the http.Handler interface, exposing an authenticated control
+// API for actors within the tree of it's Root actor.
+//
+// Requests must use the POST method, with the action as the last path segment and
+// the address of the target actor as the "addr" query parameter, for example:
+//
+//	POST /restart?addr=kit@localhost/bd9b3c6c2b8b1a2e4c5g/bd9b3c6c2b8b1a2e4c60
+//
+// The "send" action delivers the JSON decoded data of a SendRequest body into the
+// target's mailbox.
+type Control struct {
+	// Root sets the actor from which all target actors are resolved.
+	Root actorkit.Actor
+
+	// Token sets the bearer token which all requests must provide through their
+	// Authorization header. All requests are rejected if Token is empty.
+	Token string
+
+	// Events sets the EventStream into which AuditEvents are published, overriding
+	// the event stream of Root. It is optional.
+	Events actorkit.EventStream
+
+	// Decode sets the function used to decode the data of a send action, which
+	// defaults to decoding JSON into a interface{}.
+	Decode func(header actorkit.Header, data json.RawMessage) (interface{}, error)
+}
+
+// NewControl returns a new Control for provided root actor, authenticated with
+// provided token.
+func NewControl(root actorkit.Actor, token string, events actorkit.EventStream) *Control {
+	return &Control{Root: root, Token: token, Events: events}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (c *Control) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	action := r.URL.Path
+	if index := strings.LastIndex(action, "/"); index != -1 {
+		action = action[index+1:]
+	}
+
+	res := controlResponse{Action: action, Addr: r.URL.Query().Get("addr")}
+
+	if r.Method != http.MethodPost {
+		err := errors.New("method %q is not allowed", r.Method)
+		w.Header().Set("Allow", "POST")
+		c.audit(r, res, err)
+		c.respond(w, http.StatusMethodNotAllowed, res, err)
+		return
+	}
+
+	if !c.authorized(r) {
+		c.audit(r, res, ErrUnauthorized)
+		c.respond(w, http.StatusUnauthorized, res, ErrUnauthorized)
+		return
+	}
+
+	target, err := c.Root.GetAddr(res.Addr)
+	if err == nil && target.Actor() == nil {
+		err = actorkit.ErrHasNoActor
+	}
+
+	if err != nil {
+		err = errors.Wrap(err, "Failed to resolve actor %q", res.Addr)
+		c.audit(r, res, err)
+		c.respond(w, http.StatusNotFound, res, err)
+		return
+	}
+
+	status := http.StatusOK
+	if err = c.apply(action, target, r); err != nil {
+		status = http.StatusInternalServerError
+		if errors.IsAny(err, ErrUnknownAction) {
+			status = http.StatusBadRequest
+		}
+	}
+
+	res.State = target.Actor().State().String()
+	c.audit(r, res, err)
+	c.respond(w, status, res, err)
+}
+
+func (c *Control) apply(action string, target actorkit.Addr, r *http.Request) error {
+	actor := target.Actor()
+
+	switch action {
+	case StopAction:
+		return actor.Stop()
+	case KillAction:
+		return actor.Kill()
+	case RestartAction:
+		return actor.Restart()
+	case DestroyAction:
+		return actor.Destroy()
+	case StopChildrenAction:
+		return actor.StopChildren()
+	case KillChildrenAction:
+		return actor.KillChildren()
+	case RestartChildrenAction:
+		return actor.RestartChildren()
+	case DestroyChildrenAction:
+		return actor.DestroyChildren()
+	case SendAction:
+		return c.send(target, r)
+	}
+	return errors.Wrap(ErrUnknownAction, "Action %q is not supported", action)
+}
+
+func (c *Control) send(target actorkit.Addr, r *http.Request) error {
+	var req SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.Wrap(err, "Failed to decode send request")
+	}
+
+	if req.Header == nil {
+		req.Header = actorkit.Header{}
+	}
+
+	decode := c.Decode
+	if decode == nil {
+		decode = decodeJSON
+	}
+
+	data, err := decode(req.Header, req.Data)
+	if err != nil {
+		return errors.Wrap(err, "Failed to decode send data")
+	}
+
+	return target.SendWithHeader(data, req.Header, nil)
+}
+
+func (c *Control) authorized(r *http.Request) bool {
+	if c.Token == "" {
+		return false
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(c.Token)) == 1
+}
+
+// publisher defines the method used by actors to publish into their event stream.
+type publisher interface {
+	Publish(interface{})
+}
+
+func (c *Control) audit(r *http.Request, res controlResponse, err error) {
+	var events publisher
+	if c.Events != nil {
+		events = c.Events
+	} else if pub, ok := c.Root.(publisher); ok {
+		events = pub
+	}
+
+	if events == nil {
+		return
+	}
+
+	events.Publish(AuditEvent{
+		Err:    err,
+		Addr:   res.Addr,
+		Action: res.Action,
+		Remote: r.RemoteAddr,
+		Time:   time.Now(),
+	})
+}
+
+func (c *Control) respond(w http.ResponseWriter, status int, res controlResponse, err error) {
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+func decodeJSON(_ actorkit.Header, data json.RawMessage) (interface{}, error) {
+	var value interface{}
+	if len(data) == 0 {
+		return value, nil
+	}
+
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+//*****************************************************************************
+// Unix Socket
+//*****************************************************************************
+
+// ListenUnix returns a net.Listener on a unix socket at provided path, replacing
+// any stale socket and restricting access of the socket to it's owner. It fails
+// if a file which is not a socket exists at path.
+//
+// The socket is created within a temporary directory accessible only by it's
+// owner, and moved to path once it's permissions are restricted, hence it is
+// never accessible by other users.
+func ListenUnix(path string) (net.Listener, error) {
+	if err := removeSocket(path); err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".actorkit-")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create directory for socket %q", path)
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "socket")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to listen on socket %q", path)
+	}
+
+	// the socket file is moved, hence removed on close by unixListener.
+	if ul, ok := listener.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+
+	if err := os.Chmod(tmp, 0600); err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "Failed to restrict permissions of socket %q", path)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		listener.Close()
+		return nil, errors.Wrap(err, "Failed to move socket to %q", path)
+	}
+
+	return &unixListener{Listener: listener, path: path}, nil
+}
+
+// removeSocket removes a stale socket at provided path, returning an error if
+// the file at path is not a socket.
+func removeSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "Failed to stat socket %q", path)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return errors.New("File %q exists and is not a socket", path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return errors.Wrap(err, "Failed to remove stale socket %q", path)
+	}
+	return nil
+}
+
+// unixListener removes it's socket file once closed.
+type unixListener struct {
+	net.Listener
+	path string
+}
+
+// Close closes the listener, removing it's socket file.
+func (u *unixListener) Close() error {
+	err := u.Listener.Close()
+	os.Remove(u.path)
+	return err
+}
+
+// ServeUnix serves provided handler on a unix socket at provided path. It is a
+// blocking call which returns once serving fails.
+func ServeUnix(path string, handler http.Handler) error {
+	listener, err := ListenUnix(path)
+	if err != nil {
+		return err
+	}
+
+	defer listener.Close()
+	return http.Serve(listener, handler)
+}
@@ -0,0 +1,195 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/admin"
+)
+
+type recorder struct {
+	received chan actorkit.Envelope
+}
+
+func (r *recorder) Action(_ actorkit.Addr, env actorkit.Envelope) {
+	r.received <- env
+}
+
+type audits struct {
+	ml     sync.Mutex
+	events []admin.AuditEvent
+}
+
+func (a *audits) handle(event interface{}) {
+	if audit, ok := event.(admin.AuditEvent); ok {
+		a.ml.Lock()
+		a.events = append(a.events, audit)
+		a.ml.Unlock()
+	}
+}
+
+func (a *audits) Events() []admin.AuditEvent {
+	a.ml.Lock()
+	defer a.ml.Unlock()
+	return append([]admin.AuditEvent(nil), a.events...)
+}
+
+func control(t *testing.T, handler http.Handler, token string, action string, addr string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/"+action+"?addr="+addr, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestControl(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	rec := &recorder{received: make(chan actorkit.Envelope, 1)}
+	target, err := system.Spawn("target", actorkit.Prop{Behaviour: rec})
+	require.NoError(t, err)
+
+	events := actorkit.NewEventer()
+	audit := &audits{}
+	events.Subscribe(audit.handle, nil)
+
+	handler := admin.NewControl(system.Actor(), "secret", events)
+	addr := target.Actor().Addr()
+
+	res := control(t, handler, "", admin.StopAction, addr, "")
+	require.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = control(t, handler, "wrong", admin.StopAction, addr, "")
+	require.Equal(t, http.StatusUnauthorized, res.Code)
+	require.Equal(t, actorkit.RUNNING, target.Actor().State())
+
+	res = control(t, handler, "secret", admin.SendAction, addr, `{"header":{"k":"v"},"data":{"name":"wally"}}`)
+	require.Equal(t, http.StatusOK, res.Code)
+
+	env := <-rec.received
+	require.Equal(t, "v", env.Get("k"))
+	require.Equal(t, map[string]interface{}{"name": "wally"}, env.Data)
+
+	res = control(t, handler, "secret", "explode", addr, "")
+	require.Equal(t, http.StatusBadRequest, res.Code)
+
+	res = control(t, handler, "secret", admin.StopAction, system.Actor().Addr()+"/unknown", "")
+	require.Equal(t, http.StatusNotFound, res.Code)
+
+	res = control(t, handler, "secret", admin.StopAction, addr, "")
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	require.Equal(t, "STOPPED", body["state"])
+	require.Equal(t, actorkit.STOPPED, target.Actor().State())
+
+	res = control(t, handler, "secret", admin.RestartAction, addr, "")
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, actorkit.RUNNING, target.Actor().State())
+
+	recorded := audit.Events()
+	require.Len(t, recorded, 7)
+	require.Equal(t, admin.ErrUnauthorized, recorded[0].Err)
+	require.Equal(t, admin.SendAction, recorded[2].Action)
+	require.NoError(t, recorded[2].Err)
+	require.Error(t, recorded[4].Err)
+	require.Equal(t, admin.RestartAction, recorded[6].Action)
+	require.Equal(t, addr, recorded[6].Addr)
+}
+
+func TestControlRejectsWithoutToken(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	handler := admin.NewControl(system.Actor(), "", nil)
+	res := control(t, handler, "", admin.StopAction, system.Actor().Addr(), "")
+	require.Equal(t, http.StatusUnauthorized, res.Code)
+	require.Equal(t, actorkit.RUNNING, system.Actor().State())
+}
+
+func TestControlAuditsToRootEvents(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	audit := &audits{}
+	sub := system.Actor().Watch(audit.handle)
+	defer sub.Stop()
+
+	handler := admin.NewControl(system.Actor(), "secret", nil)
+
+	req := httptest.NewRequest("GET", "/"+admin.StopAction+"?addr="+system.Actor().Addr(), nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	require.Equal(t, http.StatusMethodNotAllowed, res.Code)
+
+	res = control(t, handler, "wrong", admin.StopAction, system.Actor().Addr(), "")
+	require.Equal(t, http.StatusUnauthorized, res.Code)
+
+	recorded := audit.Events()
+	require.Len(t, recorded, 2)
+	require.Error(t, recorded[0].Err)
+	require.Equal(t, admin.StopAction, recorded[0].Action)
+	require.Equal(t, admin.ErrUnauthorized, recorded[1].Err)
+}
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "admin")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	regular := filepath.Join(dir, "regular")
+	require.NoError(t, ioutil.WriteFile(regular, []byte("keep"), 0644))
+
+	_, err = admin.ListenUnix(regular)
+	require.Error(t, err)
+
+	content, err := ioutil.ReadFile(regular)
+	require.NoError(t, err)
+	require.Equal(t, "keep", string(content))
+
+	path := filepath.Join(dir, "admin.sock")
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	listener, err := admin.ListenUnix(path)
+	require.NoError(t, err)
+
+	info, err := os.Lstat(path)
+	require.NoError(t, err)
+	require.True(t, info.Mode()&os.ModeSocket != 0)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, listener.Close())
+	_, err = os.Lstat(path)
+	require.True(t, os.IsNotExist(err))
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
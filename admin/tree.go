@@ -0,0 +1,168 @@
+// Package admin provides http handlers for introspection and control of a live
+// actor hierarchy, meant to be mounted on an administrative server.
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gokit/actorkit"
+)
+
+const (
+	jsonContentType = "application/json; charset=utf-8"
+	dotContentType  = "text/vnd.graphviz; charset=utf-8"
+)
+
+// MailboxStat holds the current usage of an actor's mailbox.
+type MailboxStat struct {
+	Total int `json:"total"`
+
+	// Cap is -1 for unbounded mailboxes.
+	Cap int `json:"cap"`
+}
+
+// Node represents an actor and it's children within a snapshot of an actor tree.
+type Node struct {
+	ID        string        `json:"id"`
+	Addr      string        `json:"addr"`
+	Protocol  string        `json:"protocol"`
+	Namespace string        `json:"namespace"`
+	Parent    string        `json:"parent,omitempty"`
+	State     string        `json:"state"`
+	Stats     actorkit.Stat `json:"stats"`
+	Mailbox   MailboxStat   `json:"mailbox"`
+	Children  []*Node       `json:"children,omitempty"`
+}
+
+// Filter defines the criteria used to select actors of a tree snapshot. An actor
+// is selected if it matches all non-empty fields, and all ancestors of a selected
+// actor are retained to preserve the hierarchy.
+type Filter struct {
+	// Namespace selects actors with the same namespace.
+	Namespace string
+
+	// Path selects actors whose address starts with provided path.
+	Path string
+}
+
+// Match returns true/false if giving actor matches filter.
+func (f Filter) Match(actor actorkit.Actor) bool {
+	if f.Namespace != "" && actor.Namespace() != f.Namespace {
+		return false
+	}
+	if f.Path != "" && !strings.HasPrefix(actor.Addr(), f.Path) {
+		return false
+	}
+	return true
+}
+
+// Snapshot walks the tree of provided root actor through it's children, returning
+// the Node of root with all actors selected by provided filter. Nil is returned
+// if neither root nor any of it's descendants is selected.
+func Snapshot(root actorkit.Actor, filter Filter) *Node {
+	node := nodeOf(root)
+	for _, child := range root.Children() {
+		actor := child.Actor()
+		if actor == nil {
+			continue
+		}
+
+		if childNode := Snapshot(actor, filter); childNode != nil {
+			node.Children = append(node.Children, childNode)
+		}
+	}
+
+	if len(node.Children) == 0 && !filter.Match(root) {
+		return nil
+	}
+	return node
+}
+
+// WriteDOT writes provided node and it's children as a Graphviz DOT digraph
+// into provided writer.
+func WriteDOT(w io.Writer, root *Node) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteString("digraph actors {\n")
+	buf.WriteString("\tnode [shape=box];\n")
+	if root != nil {
+		writeDOTNode(buf, root)
+	}
+	buf.WriteString("}\n")
+	return buf.Flush()
+}
+
+func writeDOTNode(w io.Writer, node *Node) {
+	label := fmt.Sprintf("%s\\n%s\\nmailbox: %d/%d", node.Addr, node.State, node.Mailbox.Total, node.Mailbox.Cap)
+	fmt.Fprintf(w, "\t%q [label=%q];\n", node.Addr, label)
+
+	for _, child := range node.Children {
+		fmt.Fprintf(w, "\t%q -> %q;\n", node.Addr, child.Addr)
+		writeDOTNode(w, child)
+	}
+}
+
+// TreeHandler returns a http.Handler which serves a snapshot of the actor tree of
+// provided root actor.
+//
+// The following query parameters are supported:
+//
+//  1. namespace: selects actors of giving namespace.
+//  2. path: selects actors whose address starts with giving value.
+//  3. format: "dot" renders the tree as a Graphviz DOT digraph, else JSON is served.
+func TreeHandler(root actorkit.Actor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := Filter{
+			Path:      query.Get("path"),
+			Namespace: query.Get("namespace"),
+		}
+
+		node := Snapshot(root, filter)
+
+		if query.Get("format") == "dot" {
+			w.Header().Set("Content-Type", dotContentType)
+			WriteDOT(w, node)
+			return
+		}
+
+		if node == nil {
+			http.Error(w, "no actor matches filter", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", jsonContentType)
+		json.NewEncoder(w).Encode(node)
+	})
+}
+
+func nodeOf(actor actorkit.Actor) *Node {
+	node := &Node{
+		ID:        actor.ID(),
+		Addr:      actor.Addr(),
+		Protocol:  actor.Protocol(),
+		Namespace: actor.Namespace(),
+		State:     actor.State().String(),
+		Stats:     actor.Stats(),
+	}
+
+	if parent := actor.Parent(); parent != nil {
+		node.Parent = parent.Addr()
+	}
+
+	if mailbox := actor.Mailbox(); mailbox != nil {
+		node.Mailbox = MailboxStat{Total: mailbox.Total(), Cap: mailbox.Cap()}
+	}
+
+	return node
+}
@@ -0,0 +1,91 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/admin"
+)
+
+type noop struct{}
+
+func (noop) Action(_ actorkit.Addr, _ actorkit.Envelope) {}
+
+func spawnTree(t *testing.T) (actorkit.Addr, actorkit.Addr, actorkit.Addr) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+
+	users, err := system.Spawn("users", actorkit.Prop{Behaviour: noop{}})
+	require.NoError(t, err)
+
+	orders, err := system.Spawn("orders", actorkit.Prop{Behaviour: noop{}})
+	require.NoError(t, err)
+
+	return system, users, orders
+}
+
+func TestTreeHandlerJSON(t *testing.T) {
+	system, users, orders := spawnTree(t)
+	defer actorkit.Destroy(system)
+
+	recorder := httptest.NewRecorder()
+	admin.TreeHandler(system.Actor()).ServeHTTP(recorder, httptest.NewRequest("GET", "/tree", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var root admin.Node
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&root))
+	require.Equal(t, system.Actor().Addr(), root.Addr)
+	require.Equal(t, "RUNNING", root.State)
+	require.Equal(t, system.Actor().Parent().Addr(), root.Parent)
+	require.Len(t, root.Children, 2)
+
+	addrs := map[string]bool{}
+	for _, child := range root.Children {
+		addrs[child.Addr] = true
+		require.Equal(t, root.Addr, child.Parent)
+		require.Equal(t, "RUNNING", child.State)
+		require.Equal(t, -1, child.Mailbox.Cap)
+	}
+
+	require.True(t, addrs[users.Actor().Addr()])
+	require.True(t, addrs[orders.Actor().Addr()])
+}
+
+func TestTreeHandlerFilters(t *testing.T) {
+	system, users, _ := spawnTree(t)
+	defer actorkit.Destroy(system)
+
+	handler := admin.TreeHandler(system.Actor())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/tree?path="+users.Actor().Addr(), nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var root admin.Node
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&root))
+	require.Len(t, root.Children, 1)
+	require.Equal(t, users.Actor().Addr(), root.Children[0].Addr)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/tree?namespace=unknown", nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestTreeHandlerDOT(t *testing.T) {
+	system, users, _ := spawnTree(t)
+	defer actorkit.Destroy(system)
+
+	recorder := httptest.NewRecorder()
+	admin.TreeHandler(system.Actor()).ServeHTTP(recorder, httptest.NewRequest("GET", "/tree?format=dot", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	require.True(t, strings.HasPrefix(body, "digraph actors {"))
+	require.True(t, strings.Contains(body, `"`+system.Actor().Addr()+`" -> "`+users.Actor().Addr()+`";`))
+}
@@ -93,6 +93,7 @@ func (bq *BoxQueue) Clear() {
 
 	bq.tail = nil
 	bq.head = nil
+	atomic.StoreInt64(&bq.total, 0)
 	bq.pushCond.L.Unlock()
 
 	bq.pushCond.Broadcast()
@@ -143,6 +143,17 @@ func TestBoxQueue_Empty(t *testing.T) {
 	require.False(t, q.IsEmpty())
 }
 
+func TestBoxQueue_Clear(t *testing.T) {
+	q := actorkit.UnboundedBoxQueue(nil)
+	q.Push(nil, env)
+	q.Push(nil, env2)
+	require.Equal(t, 2, q.Total())
+
+	q.Clear()
+	require.True(t, q.IsEmpty())
+	require.Equal(t, 0, q.Total())
+}
+
 func TestBoundedBoxQueue_Empty(t *testing.T) {
 	q := actorkit.BoundedBoxQueue(10, actorkit.DropOld, nil)
 	require.True(t, q.IsEmpty())
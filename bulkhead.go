@@ -0,0 +1,366 @@
+package actorkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gokit/errors"
+)
+
+var (
+	// ErrBulkheadFull is returned when a bulkhead has no free slot and it's
+	// waiting queue is full.
+	ErrBulkheadFull = errors.New("Bulkhead is full")
+
+	// ErrBulkheadTimeout is returned when a call waited longer than the wait
+	// timeout of a bulkhead for a free slot.
+	ErrBulkheadTimeout = errors.New("Bulkhead wait timed out")
+)
+
+//***********************************************************
+// Bulkhead
+//***********************************************************
+
+// BulkheadConfig defines configuration values which will be used
+// by Bulkhead for it's operations.
+type BulkheadConfig struct {
+	// MaxConcurrent sets the maximum calls allowed to execute
+	// concurrently.
+	//
+	// Defaults to 10.
+	MaxConcurrent int
+
+	// MaxWaiting sets the maximum calls allowed to wait for a free
+	// slot, after which calls are rejected with ErrBulkheadFull.
+	//
+	// Defaults to 0, where calls are rejected immediately if no slot is free.
+	MaxWaiting int
+
+	// WaitTimeout sets the maximum duration a call waits for a free slot,
+	// after which it is rejected with ErrBulkheadTimeout.
+	//
+	// Defaults to 0, where calls wait until their context is done.
+	WaitTimeout time.Duration
+
+	// ReplyTimeout sets the maximum duration a delivery by a BulkheadAddr whose
+	// sender is a Future holds it's slot awaiting a reply, after which the slot
+	// is released even if the future is not resolved.
+	//
+	// Defaults to 30 seconds.
+	ReplyTimeout time.Duration
+
+	// Clock sets the Clock used for scheduling wait and reply timeouts.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
+
+	// OnReject sets giving callback to be called every time a call is
+	// rejected by bulkhead.
+	OnReject func(name string, err error)
+}
+
+func (bc *BulkheadConfig) init() {
+	if bc.MaxConcurrent <= 0 {
+		bc.MaxConcurrent = 10
+	}
+
+	if bc.MaxWaiting < 0 {
+		bc.MaxWaiting = 0
+	}
+
+	if bc.ReplyTimeout <= 0 {
+		bc.ReplyTimeout = 30 * time.Second
+	}
+
+	if bc.Clock == nil {
+		bc.Clock = SystemClock{}
+	}
+}
+
+// BulkheadStat holds a snapshot of the state and counters of a Bulkhead.
+type BulkheadStat struct {
+	Name     string
+	Active   int
+	Waiting  int
+	Accepted int64
+
+	// Rejected is the total calls rejected due to a full bulkhead, a wait
+	// timeout or a cancelled context.
+	Rejected int64
+	Full     int64
+	TimedOut int64
+}
+
+// Bulkhead implements the bulkhead pattern, isolating calls into a partition of
+// limited concurrency, where calls exceeding the limit wait within a bounded
+// queue for a free slot or are rejected.
+type Bulkhead struct {
+	name   string
+	config BulkheadConfig
+	slots  chan struct{}
+
+	accepted AtomicCounter
+	rejected AtomicCounter
+	full     AtomicCounter
+	timedOut AtomicCounter
+
+	wl      sync.Mutex
+	waiting int
+}
+
+// NewBulkhead returns a new instance of Bulkhead.
+func NewBulkhead(name string, config BulkheadConfig) *Bulkhead {
+	config.init()
+
+	return &Bulkhead{
+		name:   name,
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// Name returns the name of bulkhead.
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+// Stat returns a snapshot of the current state and counters of bulkhead.
+func (b *Bulkhead) Stat() BulkheadStat {
+	b.wl.Lock()
+	waiting := b.waiting
+	b.wl.Unlock()
+
+	return BulkheadStat{
+		Name:     b.name,
+		Active:   len(b.slots),
+		Waiting:  waiting,
+		Accepted: b.accepted.Get(),
+		Rejected: b.rejected.Get(),
+		Full:     b.full.Get(),
+		TimedOut: b.timedOut.Get(),
+	}
+}
+
+// Do executes giving function within a slot of bulkhead, waiting for a free slot
+// if none is available. If giving call is rejected, then the fallback if provided
+// is called with the rejection error.
+func (b *Bulkhead) Do(ctx context.Context, fn func(ctx context.Context) error, fallback func(context.Context, error) error) error {
+	if err := b.Acquire(ctx); err != nil {
+		if fallback == nil {
+			return err
+		}
+		return fallback(ctx, err)
+	}
+
+	defer b.Release()
+	return fn(ctx)
+}
+
+// Acquire attempts to take a slot of bulkhead, waiting for a free slot if none is
+// available. A successful Acquire must be followed by a call to Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		b.accepted.Inc()
+		return nil
+	default:
+	}
+
+	b.wl.Lock()
+	if b.waiting >= b.config.MaxWaiting {
+		b.wl.Unlock()
+		b.full.Inc()
+		return b.reject(errors.WrapOnly(ErrBulkheadFull))
+	}
+	b.waiting++
+	b.wl.Unlock()
+
+	defer func() {
+		b.wl.Lock()
+		b.waiting--
+		b.wl.Unlock()
+	}()
+
+	var expired chan struct{}
+	if b.config.WaitTimeout > 0 {
+		expired = make(chan struct{})
+		timer := b.config.Clock.AfterFunc(b.config.WaitTimeout, func() {
+			close(expired)
+		})
+		defer timer.Stop()
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		b.accepted.Inc()
+		return nil
+	case <-expired:
+		b.timedOut.Inc()
+		return b.reject(errors.WrapOnly(ErrBulkheadTimeout))
+	case <-ctx.Done():
+		return b.reject(errors.Wrap(ctx.Err(), "Bulkhead %q wait cancelled", b.name))
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (b *Bulkhead) Release() {
+	select {
+	case <-b.slots:
+	default:
+	}
+}
+
+func (b *Bulkhead) reject(err error) error {
+	b.rejected.Inc()
+	if b.config.OnReject != nil {
+		b.config.OnReject(b.name, err)
+	}
+	return err
+}
+
+//***********************************************************
+// BulkheadAddr
+//***********************************************************
+
+// BulkheadAddr implements a bulkhead Addr wrapper, which limits the concurrent
+// in-flight requests to a giving origin address. A delivery whose sender is a
+// Future holds it's slot until the future is resolved or the ReplyTimeout of
+// it's configuration elapses, giving a limit on the requests awaiting replies
+// from the address, else the slot is held for the delivery alone.
+type BulkheadAddr struct {
+	addr     Addr
+	bulkhead *Bulkhead
+
+	// Fallback defines function to be called as fallback
+	// when giving envelope is rejected by bulkhead.
+	fallback func(error, Envelope) error
+}
+
+// NewBulkheadAddr returns a new instance of a BulkheadAddr.
+func NewBulkheadAddr(addr Addr, config BulkheadConfig, fallback func(error, Envelope) error) *BulkheadAddr {
+	return &BulkheadAddr{
+		addr:     addr,
+		fallback: fallback,
+		bulkhead: NewBulkhead(addr.Addr(), config),
+	}
+}
+
+// Bulkhead returns the underline Bulkhead of address.
+func (dm *BulkheadAddr) Bulkhead() *Bulkhead {
+	return dm.bulkhead
+}
+
+// Forward attempts to forward giving envelope to underline address.
+// It returns an error if giving envelope is rejected by bulkhead, hence
+// passing envelope to fallback if provided.
+func (dm *BulkheadAddr) Forward(env Envelope) error {
+	return dm.deliver(env, func() error {
+		return dm.addr.Forward(env)
+	})
+}
+
+// Send delivers giving data as a envelope to provided underline address.
+// It returns an error if giving envelope is rejected by bulkhead, hence
+// passing envelope to fallback if provided.
+func (dm *BulkheadAddr) Send(data interface{}, addr Addr) error {
+	return dm.deliver(CreateEnvelope(addr, Header{}, data), func() error {
+		return dm.addr.Send(data, addr)
+	})
+}
+
+// SendWithHeader delivers data as a enveloped with attached headers to underline
+// address.
+// It returns an error if giving envelope is rejected by bulkhead, hence
+// passing envelope to fallback if provided.
+func (dm *BulkheadAddr) SendWithHeader(data interface{}, h Header, addr Addr) error {
+	return dm.deliver(CreateEnvelope(addr, h, data), func() error {
+		return dm.addr.SendWithHeader(data, h, addr)
+	})
+}
+
+// Ask delivers data with attached headers to underline address using a new timed
+// Future as sender, whose slot within bulkhead is held till the future is resolved
+// or timed out.
+func (dm *BulkheadAddr) Ask(data interface{}, h Header, timeout time.Duration) (Future, error) {
+	future := TimedFuture(dm.addr, timeout)
+	if err := dm.SendWithHeader(data, h, future); err != nil {
+		return nil, err
+	}
+	return future, nil
+}
+
+func (dm *BulkheadAddr) deliver(env Envelope, fn func() error) error {
+	if err := dm.bulkhead.Acquire(env.Context()); err != nil {
+		if dm.fallback == nil {
+			return err
+		}
+		return dm.fallback(err, env)
+	}
+
+	err := fn()
+
+	// requests awaiting a reply hold their slot until answered.
+	if waiter, ok := env.Sender.(Future); ok && err == nil {
+		dm.hold(waiter)
+		return nil
+	}
+
+	dm.bulkhead.Release()
+	return err
+}
+
+// hold releases the slot of a delivery once provided future is resolved or
+// the reply timeout elapses, whichever comes first.
+func (dm *BulkheadAddr) hold(waiter Future) {
+	var once sync.Once
+	release := func() {
+		once.Do(dm.bulkhead.Release)
+	}
+
+	timer := dm.bulkhead.config.Clock.AfterFunc(dm.bulkhead.config.ReplyTimeout, release)
+	waiter.PipeAction(func(Envelope) {
+		timer.Stop()
+		release()
+	})
+}
+
+//**********************************************************
+// BehaviourBulkhead
+//**********************************************************
+
+// BehaviourBulkhead implements the bulkhead pattern for the execution
+// of a Behaviour, limiting the concurrent executions of a behaviour
+// shared by many actors or which is called concurrently.
+type BehaviourBulkhead struct {
+	behaviour Behaviour
+	bulkhead  *Bulkhead
+	fallback  func(error, Addr, Envelope)
+}
+
+// NewBehaviourBulkhead returns a new instance of a BehaviourBulkhead.
+func NewBehaviourBulkhead(name string, behaviour Behaviour, config BulkheadConfig, fallback func(error, Addr, Envelope)) *BehaviourBulkhead {
+	return &BehaviourBulkhead{
+		behaviour: behaviour,
+		fallback:  fallback,
+		bulkhead:  NewBulkhead(name, config),
+	}
+}
+
+// Bulkhead returns the underline Bulkhead of behaviour.
+func (bb *BehaviourBulkhead) Bulkhead() *Bulkhead {
+	return bb.bulkhead
+}
+
+// Action implements the Behaviour interface.
+func (bb *BehaviourBulkhead) Action(addr Addr, msg Envelope) {
+	if err := bb.bulkhead.Acquire(msg.Context()); err != nil {
+		if bb.fallback != nil {
+			bb.fallback(err, addr, msg)
+		}
+		return
+	}
+
+	defer bb.bulkhead.Release()
+	bb.behaviour.Action(addr, msg)
+}
@@ -0,0 +1,170 @@
+package actorkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkheadRejectsWhenFull(t *testing.T) {
+	var rejections int
+	bulkhead := actorkit.NewBulkhead("db", actorkit.BulkheadConfig{
+		MaxConcurrent: 1,
+		OnReject: func(name string, err error) {
+			rejections++
+		},
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- bulkhead.Do(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}, nil)
+	}()
+
+	<-started
+
+	var fallbackErr error
+	require.NoError(t, bulkhead.Do(context.Background(), func(ctx context.Context) error {
+		require.Fail(t, "Should not be executed")
+		return nil
+	}, func(ctx context.Context, err error) error {
+		fallbackErr = err
+		return nil
+	}))
+	require.Error(t, fallbackErr)
+	require.Equal(t, 1, rejections)
+
+	stat := bulkhead.Stat()
+	require.Equal(t, 1, stat.Active)
+	require.Equal(t, int64(1), stat.Accepted)
+	require.Equal(t, int64(1), stat.Rejected)
+	require.Equal(t, int64(1), stat.Full)
+
+	close(release)
+	require.NoError(t, <-done)
+	require.Equal(t, 0, bulkhead.Stat().Active)
+}
+
+func TestBulkheadWaitsForSlot(t *testing.T) {
+	bulkhead := actorkit.NewBulkhead("db", actorkit.BulkheadConfig{
+		MaxConcurrent: 1,
+		MaxWaiting:    1,
+		WaitTimeout:   50 * time.Millisecond,
+	})
+
+	require.NoError(t, bulkhead.Acquire(context.Background()))
+
+	// waiting call times out.
+	require.Error(t, bulkhead.Acquire(context.Background()))
+	require.Equal(t, int64(1), bulkhead.Stat().TimedOut)
+
+	// waiting call is cancelled by it's context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, bulkhead.Acquire(ctx))
+
+	// waiting call takes slot once released.
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- bulkhead.Acquire(context.Background())
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 1, bulkhead.Stat().Waiting)
+	bulkhead.Release()
+	require.NoError(t, <-acquired)
+
+	stat := bulkhead.Stat()
+	require.Equal(t, 0, stat.Waiting)
+	require.Equal(t, int64(2), stat.Accepted)
+	require.Equal(t, int64(2), stat.Rejected)
+}
+
+func TestBulkheadAddrHoldsSlotTillReply(t *testing.T) {
+	release := make(chan struct{})
+	am := actorkit.FromFunc("ns", "replier", func(addr actorkit.Addr, env actorkit.Envelope) {
+		<-release
+		env.Sender.Send("pong", addr)
+	})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	var dropped int
+	addr := actorkit.NewBulkheadAddr(actorkit.AddressOf(am, "replier"), actorkit.BulkheadConfig{
+		MaxConcurrent: 1,
+	}, func(err error, env actorkit.Envelope) error {
+		dropped++
+		return err
+	})
+
+	future, err := addr.Ask("ping", actorkit.Header{}, time.Second)
+	require.NoError(t, err)
+
+	// slot is held while first request awaits it's reply.
+	_, err = addr.Ask("ping", actorkit.Header{}, time.Second)
+	require.Error(t, err)
+	require.Equal(t, 1, dropped)
+
+	close(release)
+	require.NoError(t, future.Wait())
+	require.Equal(t, "pong", future.Result().Data)
+
+	for i := 0; i < 100 && addr.Bulkhead().Stat().Active != 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Equal(t, 0, addr.Bulkhead().Stat().Active)
+
+	future, err = addr.Ask("ping", actorkit.Header{}, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, future.Wait())
+}
+
+func TestBehaviourBulkhead(t *testing.T) {
+	base := &basic{Message: make(chan *actorkit.Envelope, 1)}
+
+	var rejected int
+	behaviour := actorkit.NewBehaviourBulkhead("basic", base, actorkit.BulkheadConfig{MaxConcurrent: 1}, func(err error, addr actorkit.Addr, env actorkit.Envelope) {
+		rejected++
+	})
+
+	require.NoError(t, behaviour.Bulkhead().Acquire(context.Background()))
+	behaviour.Action(nil, actorkit.CreateEnvelope(nil, actorkit.Header{}, 1))
+	require.Equal(t, 1, rejected)
+	require.Len(t, base.Message, 0)
+
+	behaviour.Bulkhead().Release()
+	behaviour.Action(nil, actorkit.CreateEnvelope(nil, actorkit.Header{}, 2))
+	require.Equal(t, 2, (<-base.Message).Data)
+}
+
+func TestBulkheadAddrReleasesUnansweredSlot(t *testing.T) {
+	am := actorkit.FromFunc("ns", "silent", func(addr actorkit.Addr, env actorkit.Envelope) {})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	silent := actorkit.AddressOf(am, "silent")
+	addr := actorkit.NewBulkheadAddr(silent, actorkit.BulkheadConfig{
+		MaxConcurrent: 1,
+		ReplyTimeout:  20 * time.Millisecond,
+	}, nil)
+
+	// a future without timeout which is never answered.
+	require.NoError(t, addr.Send("ping", actorkit.NewFuture(silent)))
+	require.Equal(t, 1, addr.Bulkhead().Stat().Active)
+	require.Error(t, addr.Send("ping", actorkit.NewFuture(silent)))
+
+	for i := 0; i < 100 && addr.Bulkhead().Stat().Active != 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Equal(t, 0, addr.Bulkhead().Stat().Active)
+	require.NoError(t, addr.Send("ping", actorkit.NewFuture(silent)))
+}
@@ -0,0 +1,172 @@
+package actorkit
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gokit/errors"
+)
+
+// ErrCircuitNotFound is returned when a CircuitRegistry has no circuit of a giving name.
+var ErrCircuitNotFound = errors.New("Circuit not found")
+
+//***********************************************************
+// CircuitRegistry
+//***********************************************************
+
+// CircuitRegistry manages a set of named CircuitBreakers, giving a single place
+// to retrieve, inspect and manually open or close all circuits of a system.
+//
+// All circuits created by a registry publish their CircuitTransition events on
+// the registry's EventStream.
+type CircuitRegistry struct {
+	defaults Circuit
+	events   EventStream
+
+	ml       sync.RWMutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewCircuitRegistry returns a new instance of a CircuitRegistry, which uses provided
+// Circuit as configuration for circuits created without one. If events is nil, then
+// a new EventStream is created.
+func NewCircuitRegistry(defaults Circuit, events EventStream) *CircuitRegistry {
+	if events == nil {
+		events = NewEventer()
+	}
+
+	return &CircuitRegistry{
+		defaults: defaults,
+		events:   events,
+		breakers: map[string]*CircuitBreaker{},
+	}
+}
+
+// Events returns the EventStream on which all CircuitTransition of registered
+// circuits are published.
+func (cr *CircuitRegistry) Events() EventStream {
+	return cr.events
+}
+
+// Breaker returns the CircuitBreaker of giving name, creating one using the
+// default Circuit of registry if none exists.
+func (cr *CircuitRegistry) Breaker(name string) *CircuitBreaker {
+	return cr.BreakerWith(name, cr.defaults)
+}
+
+// BreakerWith returns the CircuitBreaker of giving name, creating one using provided
+// Circuit if none exists. An existing circuit keeps it's original configuration.
+func (cr *CircuitRegistry) BreakerWith(name string, circuit Circuit) *CircuitBreaker {
+	cr.ml.RLock()
+	breaker, ok := cr.breakers[name]
+	cr.ml.RUnlock()
+
+	if ok {
+		return breaker
+	}
+
+	cr.ml.Lock()
+	defer cr.ml.Unlock()
+
+	if breaker, ok := cr.breakers[name]; ok {
+		return breaker
+	}
+
+	breaker = NewCircuitBreaker(name, circuit)
+	breaker.events = cr.events
+	cr.breakers[name] = breaker
+	return breaker
+}
+
+// Get returns the CircuitBreaker of giving name if it exists.
+func (cr *CircuitRegistry) Get(name string) (*CircuitBreaker, bool) {
+	cr.ml.RLock()
+	defer cr.ml.RUnlock()
+
+	breaker, ok := cr.breakers[name]
+	return breaker, ok
+}
+
+// Remove removes the CircuitBreaker of giving name from registry.
+func (cr *CircuitRegistry) Remove(name string) {
+	cr.ml.Lock()
+	delete(cr.breakers, name)
+	cr.ml.Unlock()
+}
+
+// Names returns the sorted names of all registered circuits.
+func (cr *CircuitRegistry) Names() []string {
+	cr.ml.RLock()
+	names := make([]string, 0, len(cr.breakers))
+	for name := range cr.breakers {
+		names = append(names, name)
+	}
+	cr.ml.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// Stats returns a snapshot of all registered circuits, sorted by name.
+func (cr *CircuitRegistry) Stats() []CircuitStat {
+	names := cr.Names()
+
+	stats := make([]CircuitStat, 0, len(names))
+	for _, name := range names {
+		if breaker, ok := cr.Get(name); ok {
+			stats = append(stats, breaker.Stat())
+		}
+	}
+	return stats
+}
+
+// Stat returns a snapshot of the circuit of giving name.
+func (cr *CircuitRegistry) Stat(name string) (CircuitStat, error) {
+	breaker, ok := cr.Get(name)
+	if !ok {
+		return CircuitStat{}, errors.Wrap(ErrCircuitNotFound, "Circuit %q not found", name)
+	}
+	return breaker.Stat(), nil
+}
+
+// ForceOpen forces the circuit of giving name into opened state.
+func (cr *CircuitRegistry) ForceOpen(name string) error {
+	breaker, ok := cr.Get(name)
+	if !ok {
+		return errors.Wrap(ErrCircuitNotFound, "Circuit %q not found", name)
+	}
+
+	breaker.ForceOpen()
+	return nil
+}
+
+// ForceClose forces the circuit of giving name into closed state.
+func (cr *CircuitRegistry) ForceClose(name string) error {
+	breaker, ok := cr.Get(name)
+	if !ok {
+		return errors.Wrap(ErrCircuitNotFound, "Circuit %q not found", name)
+	}
+
+	breaker.ForceClose()
+	return nil
+}
+
+// Addr returns a new CircuitAddr for provided address using the circuit of giving
+// name, which is created with the default Circuit of registry if none exists.
+func (cr *CircuitRegistry) Addr(name string, addr Addr, fallback func(error, Envelope) error) *CircuitAddr {
+	return &CircuitAddr{
+		addr:     addr,
+		fallback: fallback,
+		circuit:  cr.Breaker(name),
+	}
+}
+
+// Behaviour returns a new BehaviourCircuit for provided behaviour using the circuit of
+// giving name, which is created with the default Circuit of registry if none exists.
+func (cr *CircuitRegistry) Behaviour(name string, behaviour ErrorBehaviour, fallback func(Addr, Envelope) error) *BehaviourCircuit {
+	return &BehaviourCircuit{
+		behaviour: behaviour,
+		fallback:  fallback,
+		circuit:   cr.Breaker(name),
+	}
+}
@@ -0,0 +1,128 @@
+package actorkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitRegistry(t *testing.T) {
+	now := time.Now()
+	registry := actorkit.NewCircuitRegistry(actorkit.Circuit{
+		MaxFailures: 2,
+		MinCoolDown: time.Second,
+		MaxCoolDown: 2 * time.Second,
+		Now: func() time.Time {
+			return now
+		},
+	}, nil)
+
+	transitions := make(chan actorkit.CircuitTransition, 10)
+	sub := registry.Events().Subscribe(func(m interface{}) {
+		transitions <- m.(actorkit.CircuitTransition)
+	}, nil)
+	defer sub.Stop()
+
+	db := registry.Breaker("db")
+	require.Equal(t, db, registry.Breaker("db"))
+	registry.Breaker("cache")
+	require.Equal(t, []string{"cache", "db"}, registry.Names())
+
+	bad := errors.New("bad")
+	for i := 0; i < 2; i++ {
+		require.Error(t, db.Do(context.Background(), func(ctx context.Context) error {
+			return bad
+		}, nil))
+	}
+
+	require.Equal(t, actorkit.CircuitOpened, db.State())
+	require.Error(t, db.Do(context.Background(), func(ctx context.Context) error {
+		return nil
+	}, nil))
+
+	opened := <-transitions
+	require.Equal(t, "db", opened.Name)
+	require.Equal(t, actorkit.CircuitClosed, opened.From)
+	require.Equal(t, actorkit.CircuitOpened, opened.To)
+	require.Equal(t, bad, opened.Err)
+
+	stat, err := registry.Stat("db")
+	require.NoError(t, err)
+	require.Equal(t, actorkit.CircuitOpened, stat.State)
+	require.Equal(t, int64(2), stat.Calls)
+	require.Equal(t, int64(2), stat.Failures)
+	require.Equal(t, int64(1), stat.Rejections)
+	require.Equal(t, bad, stat.LastError)
+
+	now = now.Add(2 * time.Second)
+	require.NoError(t, db.Do(context.Background(), func(ctx context.Context) error {
+		return nil
+	}, nil))
+	require.Equal(t, actorkit.CircuitHalfOpened, (<-transitions).To)
+	require.Equal(t, actorkit.CircuitClosed, (<-transitions).To)
+
+	stats := registry.Stats()
+	require.Len(t, stats, 2)
+	require.Equal(t, "cache", stats[0].Name)
+	require.Equal(t, actorkit.CircuitClosed, stats[1].State)
+	require.Equal(t, int64(1), stats[1].Successes)
+
+	_, err = registry.Stat("unknown")
+	require.Error(t, err)
+	require.Error(t, registry.ForceOpen("unknown"))
+}
+
+func TestCircuitRegistryForceOpenAndClose(t *testing.T) {
+	now := time.Now()
+	registry := actorkit.NewCircuitRegistry(actorkit.Circuit{
+		MinCoolDown: time.Second,
+		MaxCoolDown: 2 * time.Second,
+		Now: func() time.Time {
+			return now
+		},
+	}, nil)
+
+	transitions := make(chan actorkit.CircuitTransition, 10)
+	sub := registry.Events().Subscribe(func(m interface{}) {
+		transitions <- m.(actorkit.CircuitTransition)
+	}, nil)
+	defer sub.Stop()
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 1)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	var dropped int
+	addr := registry.Addr("deliveries", actorkit.AddressOf(am, "basic"), func(err error, env actorkit.Envelope) error {
+		dropped++
+		return err
+	})
+
+	require.NoError(t, registry.ForceOpen("deliveries"))
+
+	forced := <-transitions
+	require.True(t, forced.Forced)
+	require.Equal(t, actorkit.CircuitOpened, forced.To)
+
+	// forced circuits stay opened past their cool down.
+	now = now.Add(time.Minute)
+	require.Error(t, addr.Send(1, nil))
+	require.Equal(t, 1, dropped)
+
+	stat, err := registry.Stat("deliveries")
+	require.NoError(t, err)
+	require.True(t, stat.Forced)
+	require.Equal(t, int64(1), stat.Rejections)
+
+	require.NoError(t, registry.ForceClose("deliveries"))
+	require.Equal(t, actorkit.CircuitClosed, (<-transitions).To)
+
+	require.NoError(t, addr.Send(2, nil))
+	content := <-base.Message
+	require.Equal(t, 2, content.Data)
+}
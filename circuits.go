@@ -2,6 +2,7 @@ package actorkit
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/gokit/errors"
@@ -14,8 +15,81 @@ var (
 	// ErrOpAfterTimeout is returned when operation call executes longer than
 	// timeout duration.
 	ErrOpAfterTimeout = errors.New("operation finished after timeout")
+
+	// ErrSlowCallRate is provided to Circuit.OnTrip when a circuit trips due to
+	// it's rate of slow calls.
+	ErrSlowCallRate = errors.New("slow call rate exceeded threshold")
+
+	// ErrFailureRate is provided to Circuit.OnTrip when a circuit trips due to
+	// it's rate of failed calls on a successful call.
+	ErrFailureRate = errors.New("failure rate exceeded threshold")
 )
 
+//***********************************************************
+// CircuitState
+//***********************************************************
+
+// CircuitState defines the state of a CircuitBreaker.
+type CircuitState int
+
+// constants of circuit states.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpened
+	CircuitHalfOpened
+)
+
+// String returns the name of giving state.
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpened:
+		return "opened"
+	case CircuitHalfOpened:
+		return "half-opened"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitTransition is published on the EventStream of a CircuitBreaker
+// every time it transitions between states.
+type CircuitTransition struct {
+	Name string
+	From CircuitState
+	To   CircuitState
+	Time time.Time
+
+	// Forced is true when transition was requested manually through
+	// CircuitBreaker.ForceOpen or CircuitBreaker.ForceClose.
+	Forced bool
+
+	// Err is the error which tripped the circuit, if any.
+	Err error
+}
+
+// CircuitStat holds a snapshot of the state and counters of a CircuitBreaker.
+type CircuitStat struct {
+	Name       string
+	State      CircuitState
+	Forced     bool
+	LastError  error
+	LastOpened time.Time
+
+	// Calls is the total calls executed by circuit, excluding rejected calls.
+	Calls      int64
+	Successes  int64
+	Failures   int64
+	Rejections int64
+
+	// FailureRate, SlowCallRate and WindowCalls hold the rates and total calls
+	// within the sliding window of a circuit in rate mode.
+	FailureRate  float64
+	SlowCallRate float64
+	WindowCalls  int
+}
+
 //***********************************************************
 // CircuitBreaker
 //***********************************************************
@@ -80,6 +154,44 @@ type Circuit struct {
 	// OnHalfOpen sets giving callback to be called every time
 	// circuit enters half open state.
 	OnHalfOpen func(name string, lastCoolDown time.Duration, lastOpenedTime time.Time)
+
+	// FailureRateThreshold sets the percentage (0, 100] of failed calls within
+	// the sliding window at or above which the circuit trips.
+	//
+	// Setting FailureRateThreshold or SlowCallRateThreshold switches circuit
+	// into rate mode, where MaxFailures is ignored.
+	FailureRateThreshold float64
+
+	// SlowCallRateThreshold sets the percentage (0, 100] of slow calls within
+	// the sliding window at or above which the circuit trips.
+	SlowCallRateThreshold float64
+
+	// SlowCallDuration sets the duration at or above which a call is slow.
+	//
+	// Defaults to Timeout if set, else 60 seconds.
+	SlowCallDuration time.Duration
+
+	// WindowSize sets the number of last calls within the sliding window of
+	// a count based window.
+	//
+	// Defaults to 100.
+	WindowSize int
+
+	// WindowDuration sets the duration of a time based sliding window, which
+	// holds calls made within the duration. Setting WindowDuration switches
+	// the sliding window from count based to time based.
+	WindowDuration time.Duration
+
+	// MinimumCalls sets the minimum calls within the sliding window before
+	// rates are evaluated for tripping the circuit.
+	//
+	// Defaults to 10.
+	MinimumCalls int
+}
+
+// rateMode returns true/false if circuit trips on rates of failed and slow calls.
+func (cb *Circuit) rateMode() bool {
+	return cb.FailureRateThreshold > 0 || cb.SlowCallRateThreshold > 0
 }
 
 func (cb *Circuit) init() {
@@ -108,6 +220,99 @@ func (cb *Circuit) init() {
 			return true
 		}
 	}
+
+	if cb.SlowCallDuration <= 0 {
+		cb.SlowCallDuration = cb.Timeout
+	}
+
+	if cb.SlowCallDuration <= 0 {
+		cb.SlowCallDuration = 60 * time.Second
+	}
+
+	if cb.WindowSize <= 0 {
+		cb.WindowSize = 100
+	}
+
+	if cb.MinimumCalls <= 0 {
+		cb.MinimumCalls = 10
+	}
+}
+
+//***********************************************************
+// slidingWindow
+//***********************************************************
+
+// callOutcome holds the outcome of a call recorded within a slidingWindow.
+type callOutcome struct {
+	at     time.Time
+	failed bool
+	slow   bool
+}
+
+// slidingWindow holds the outcomes of either the last size calls or calls
+// made within the last duration if duration is set.
+type slidingWindow struct {
+	size     int
+	duration time.Duration
+
+	ml    sync.Mutex
+	calls []callOutcome
+}
+
+// record adds provided outcome into window, returning the total calls, failed
+// calls and slow calls within window.
+func (w *slidingWindow) record(outcome callOutcome) (int, int, int) {
+	w.ml.Lock()
+	defer w.ml.Unlock()
+
+	w.calls = append(w.calls, outcome)
+	w.evict(outcome.at)
+	return w.counts()
+}
+
+// stats returns the total calls, failed calls and slow calls within window at
+// provided time.
+func (w *slidingWindow) stats(now time.Time) (int, int, int) {
+	w.ml.Lock()
+	defer w.ml.Unlock()
+
+	w.evict(now)
+	return w.counts()
+}
+
+func (w *slidingWindow) reset() {
+	w.ml.Lock()
+	w.calls = nil
+	w.ml.Unlock()
+}
+
+func (w *slidingWindow) evict(now time.Time) {
+	var drop int
+	if w.duration > 0 {
+		start := now.Add(-w.duration)
+		for drop < len(w.calls) && !w.calls[drop].at.After(start) {
+			drop++
+		}
+	} else if len(w.calls) > w.size {
+		drop = len(w.calls) - w.size
+	}
+
+	if drop > 0 {
+		w.calls = append(w.calls[:0], w.calls[drop:]...)
+	}
+}
+
+func (w *slidingWindow) counts() (int, int, int) {
+	var failed, slow int
+	for _, call := range w.calls {
+		if call.failed {
+			failed++
+		}
+		if call.slow {
+			slow++
+		}
+	}
+	return len(w.calls), failed, slow
 }
 
 //***********************************************************
@@ -128,6 +333,21 @@ type CircuitBreaker struct {
 	isHalfOpened            AtomicBool
 	halfOpenedPasses        AtomicCounter
 	currentHalfOpenFailures AtomicCounter
+
+	window *slidingWindow
+
+	forced     AtomicBool
+	calls      AtomicCounter
+	successes  AtomicCounter
+	failures   AtomicCounter
+	rejections AtomicCounter
+
+	// events if set, will receive all CircuitTransition of circuit.
+	events EventStream
+
+	sl        sync.Mutex
+	state     CircuitState
+	lastError error
 }
 
 // NewCircuitBreaker returns a new instance of CircuitBreaker.
@@ -137,7 +357,21 @@ func NewCircuitBreaker(name string, circuit Circuit) *CircuitBreaker {
 	return &CircuitBreaker{
 		name:    name,
 		circuit: circuit,
+		window: &slidingWindow{
+			size:     circuit.WindowSize,
+			duration: circuit.WindowDuration,
+		},
+	}
+}
+
+// Rates returns the percentage of failed calls and slow calls within the sliding
+// window of a circuit in rate mode, and the total calls within the window.
+func (dm *CircuitBreaker) Rates() (failureRate float64, slowCallRate float64, calls int) {
+	total, failed, slow := dm.window.stats(dm.circuit.Now())
+	if total == 0 {
+		return 0, 0, 0
 	}
+	return percentOf(failed, total), percentOf(slow, total), total
 }
 
 // IsOpened returns true/false if circuit is in opened state.
@@ -145,6 +379,108 @@ func (dm *CircuitBreaker) IsOpened() bool {
 	return dm.isOpened.IsTrue()
 }
 
+// Name returns the name of circuit.
+func (dm *CircuitBreaker) Name() string {
+	return dm.name
+}
+
+// State returns the current state of circuit.
+func (dm *CircuitBreaker) State() CircuitState {
+	dm.sl.Lock()
+	defer dm.sl.Unlock()
+	return dm.state
+}
+
+// Stat returns a snapshot of the current state and counters of circuit.
+func (dm *CircuitBreaker) Stat() CircuitStat {
+	failureRate, slowCallRate, windowCalls := dm.Rates()
+
+	dm.sl.Lock()
+	state, lastError, lastOpened := dm.state, dm.lastError, dm.lastOpened
+	dm.sl.Unlock()
+
+	return CircuitStat{
+		Name:         dm.name,
+		State:        state,
+		Forced:       dm.forced.IsTrue(),
+		LastError:    lastError,
+		LastOpened:   lastOpened,
+		Calls:        dm.calls.Get(),
+		Successes:    dm.successes.Get(),
+		Failures:     dm.failures.Get(),
+		Rejections:   dm.rejections.Get(),
+		FailureRate:  failureRate,
+		SlowCallRate: slowCallRate,
+		WindowCalls:  windowCalls,
+	}
+}
+
+// ForceOpen puts circuit into opened state, rejecting all calls until
+// ForceClose is called, regardless of cool down periods.
+func (dm *CircuitBreaker) ForceOpen() {
+	dm.forced.On()
+	dm.isOpened.On()
+	dm.isHalfOpened.Off()
+	dm.halfOpenedPasses.Set(0)
+	dm.setLastOpened(dm.circuit.Now())
+	dm.transition(CircuitOpened, true, nil)
+}
+
+// ForceClose puts circuit into closed state, releasing a previous ForceOpen
+// and resetting all failures recorded by circuit.
+func (dm *CircuitBreaker) ForceClose() {
+	dm.forced.Off()
+	wasOpened := dm.isOpened.IsTrue()
+
+	dm.isOpened.Off()
+	dm.isHalfOpened.Off()
+	dm.window.reset()
+	dm.currentFailures.Set(0)
+	dm.halfOpenedPasses.Set(0)
+	dm.currentHalfOpenFailures.Set(0)
+	dm.nextCoolDown.Set(dm.circuit.MinCoolDown.Nanoseconds())
+
+	if wasOpened && dm.circuit.OnClose != nil {
+		dm.circuit.OnClose(dm.name, dm.nextCoolDown.GetDuration())
+	}
+
+	dm.transition(CircuitClosed, true, nil)
+}
+
+// transition moves circuit into provided state, publishing a CircuitTransition
+// if the state differs from current state.
+func (dm *CircuitBreaker) transition(to CircuitState, forced bool, err error) {
+	dm.sl.Lock()
+	from := dm.state
+	dm.state = to
+	dm.sl.Unlock()
+
+	if from == to || dm.events == nil {
+		return
+	}
+
+	dm.events.Publish(CircuitTransition{
+		Name:   dm.name,
+		From:   from,
+		To:     to,
+		Forced: forced,
+		Err:    err,
+		Time:   dm.circuit.Now(),
+	})
+}
+
+func (dm *CircuitBreaker) setLastOpened(t time.Time) {
+	dm.sl.Lock()
+	dm.lastOpened = t
+	dm.sl.Unlock()
+}
+
+func (dm *CircuitBreaker) getLastOpened() time.Time {
+	dm.sl.Lock()
+	defer dm.sl.Unlock()
+	return dm.lastOpened
+}
+
 // Do will attempt to execute giving function with a timed function if CircuitBreaker provides
 // a timeout.
 //
@@ -166,6 +502,8 @@ func (dm *CircuitBreaker) Do(parentCtx context.Context, fn func(ctx context.Cont
 	}
 
 	if !dm.shouldTry() {
+		dm.rejections.Inc()
+
 		if fallback == nil {
 			cancel()
 			return errors.WrapOnly(ErrOpenedCircuit)
@@ -200,7 +538,14 @@ func (dm *CircuitBreaker) run(ctx context.Context, parentCtx context.Context, fn
 	// get duration of call.
 	elapsed := end.Sub(start)
 
+	dm.calls.Inc()
+
 	if runErr != nil {
+		dm.failures.Inc()
+		dm.sl.Lock()
+		dm.lastError = runErr
+		dm.sl.Unlock()
+
 		if dm.circuit.OnRun != nil {
 			dm.circuit.OnRun(dm.name, start, end, runErr)
 		}
@@ -208,6 +553,8 @@ func (dm *CircuitBreaker) run(ctx context.Context, parentCtx context.Context, fn
 		// if we have an error, then register error.
 		if dm.isOpened.IsTrue() {
 			dm.recordHalfOpenFailure(runErr)
+		} else if dm.circuit.rateMode() {
+			dm.recordCall(runErr, elapsed, end)
 		} else {
 			dm.recordFailure(runErr)
 		}
@@ -226,13 +573,20 @@ func (dm *CircuitBreaker) run(ctx context.Context, parentCtx context.Context, fn
 	}
 
 	// run OnRun with no error
-	if elapsed > dm.circuit.Timeout {
+	if dm.circuit.Timeout > 0 && elapsed > dm.circuit.Timeout {
+		dm.failures.Inc()
+		dm.sl.Lock()
+		dm.lastError = ErrOpAfterTimeout
+		dm.sl.Unlock()
+
 		if dm.circuit.OnRun != nil {
 			dm.circuit.OnRun(dm.name, start, end, ErrOpAfterTimeout)
 		}
 
 		if dm.isOpened.IsTrue() {
 			dm.recordHalfOpenFailure(ErrOpAfterTimeout)
+		} else if dm.circuit.rateMode() {
+			dm.recordCall(ErrOpAfterTimeout, elapsed, end)
 		} else {
 			dm.recordFailure(ErrOpAfterTimeout)
 		}
@@ -240,8 +594,16 @@ func (dm *CircuitBreaker) run(ctx context.Context, parentCtx context.Context, fn
 		return errors.WrapOnly(ErrOpAfterTimeout)
 	}
 
+	dm.successes.Inc()
+
 	if dm.isOpened.IsTrue() {
-		dm.recordHalfOpenSuccess()
+		if dm.circuit.SlowCallRateThreshold > 0 && elapsed >= dm.circuit.SlowCallDuration {
+			dm.recordHalfOpenFailure(ErrSlowCallRate)
+		} else {
+			dm.recordHalfOpenSuccess()
+		}
+	} else if dm.circuit.rateMode() {
+		dm.recordCall(nil, elapsed, end)
 	}
 
 	if dm.circuit.OnRun != nil {
@@ -265,33 +627,84 @@ func (dm *CircuitBreaker) recordFailure(err error) {
 
 	// if we have maxed possible failures then put us into open state.
 	if dm.currentFailures.Get() >= dm.circuit.MaxFailures {
-		dm.isOpened.On()
-		dm.halfOpenedPasses.Set(0)
-		dm.lastOpened = dm.circuit.Now()
-		dm.currentHalfOpenFailures.Set(0)
-		dm.nextCoolDown.Set(dm.circuit.MinCoolDown.Nanoseconds())
-		if dm.circuit.OnTrip != nil {
-			dm.circuit.OnTrip(dm.name, err)
+		dm.trip(err)
+	}
+}
+
+// recordCall records the outcome of a call into the sliding window of a circuit
+// in rate mode, tripping the circuit if either the failure or slow call rate
+// reach their thresholds.
+func (dm *CircuitBreaker) recordCall(err error, elapsed time.Duration, end time.Time) {
+	// errors which can not trigger us are not recorded at all.
+	if err != nil && dm.circuit.CanTrigger != nil && !dm.circuit.CanTrigger(err) {
+		return
+	}
+
+	total, failed, slow := dm.window.record(callOutcome{
+		at:     end,
+		failed: err != nil,
+		slow:   elapsed >= dm.circuit.SlowCallDuration,
+	})
+
+	if total < dm.circuit.MinimumCalls {
+		return
+	}
+
+	if dm.circuit.FailureRateThreshold > 0 && percentOf(failed, total) >= dm.circuit.FailureRateThreshold {
+		if err == nil {
+			err = ErrFailureRate
 		}
+		dm.trip(err)
+		return
 	}
+
+	if dm.circuit.SlowCallRateThreshold > 0 && percentOf(slow, total) >= dm.circuit.SlowCallRateThreshold {
+		dm.trip(ErrSlowCallRate)
+	}
+}
+
+// trip puts circuit into open state.
+func (dm *CircuitBreaker) trip(err error) {
+	dm.isOpened.On()
+	dm.isHalfOpened.Off()
+	dm.halfOpenedPasses.Set(0)
+	dm.setLastOpened(dm.circuit.Now())
+	dm.currentHalfOpenFailures.Set(0)
+	dm.nextCoolDown.Set(dm.circuit.MinCoolDown.Nanoseconds())
+	if dm.circuit.OnTrip != nil {
+		dm.circuit.OnTrip(dm.name, err)
+	}
+	dm.transition(CircuitOpened, false, err)
 }
 
 func (dm *CircuitBreaker) recordHalfOpenSuccess() {
+	// calls which were in flight when circuit was forced opened can not close it.
+	if dm.forced.IsTrue() {
+		return
+	}
+
 	dm.halfOpenedPasses.Inc()
 	if dm.halfOpenedPasses.Get() >= dm.circuit.HalfOpenSuccess {
 		dm.isOpened.Off()
+		dm.isHalfOpened.Off()
 
 		if dm.circuit.OnClose != nil {
 			dm.circuit.OnClose(dm.name, dm.nextCoolDown.GetDuration())
 		}
 
+		dm.window.reset()
 		dm.currentFailures.Set(0)
 		dm.halfOpenedPasses.Set(0)
 		dm.currentHalfOpenFailures.Set(0)
 		dm.nextCoolDown.Set(dm.circuit.MinCoolDown.Nanoseconds())
+		dm.transition(CircuitClosed, false, nil)
 	}
 }
 
+func percentOf(count int, total int) float64 {
+	return float64(count) * 100 / float64(total)
+}
+
 func (dm *CircuitBreaker) recordHalfOpenFailure(err error) {
 	// If we have a non-nil error value, should this be
 	// something that can trigger us?
@@ -306,7 +719,8 @@ func (dm *CircuitBreaker) recordHalfOpenFailure(err error) {
 	dm.currentHalfOpenFailures.Inc()
 
 	// update last opened timestamp.
-	dm.lastOpened = dm.circuit.Now()
+	dm.setLastOpened(dm.circuit.Now())
+	dm.isHalfOpened.Off()
 
 	// increment next cool down time.
 	nextCoolDown := dm.circuit.MinCoolDown * dm.currentHalfOpenFailures.GetDuration()
@@ -315,6 +729,7 @@ func (dm *CircuitBreaker) recordHalfOpenFailure(err error) {
 	if dm.circuit.OnTrip != nil {
 		dm.circuit.OnTrip(dm.name, err)
 	}
+	dm.transition(CircuitOpened, false, err)
 }
 
 func (dm *CircuitBreaker) shouldTry() bool {
@@ -322,7 +737,12 @@ func (dm *CircuitBreaker) shouldTry() bool {
 		return true
 	}
 
-	past := dm.circuit.Now().Sub(dm.lastOpened)
+	if dm.forced.IsTrue() {
+		return false
+	}
+
+	lastOpened := dm.getLastOpened()
+	past := dm.circuit.Now().Sub(lastOpened)
 	nextCool := dm.nextCoolDown.GetDuration()
 
 	// if we have reached or maxed current next cool down,
@@ -331,10 +751,12 @@ func (dm *CircuitBreaker) shouldTry() bool {
 
 		// Trigger update for half opened state.
 		if dm.circuit.OnHalfOpen != nil {
-			dm.circuit.OnHalfOpen(dm.name, nextCool, dm.lastOpened)
+			dm.circuit.OnHalfOpen(dm.name, nextCool, lastOpened)
 		}
 
-		dm.lastOpened = dm.circuit.Now()
+		dm.setLastOpened(dm.circuit.Now())
+		dm.isHalfOpened.On()
+		dm.transition(CircuitHalfOpened, false, nil)
 		return true
 	}
 
@@ -435,6 +857,15 @@ type BehaviourCircuit struct {
 	fallback  func(Addr, Envelope) error
 }
 
+// NewBehaviourCircuit returns a new instance of a BehaviourCircuit.
+func NewBehaviourCircuit(name string, behaviour ErrorBehaviour, circuit Circuit, fallback func(Addr, Envelope) error) *BehaviourCircuit {
+	return &BehaviourCircuit{
+		behaviour: behaviour,
+		fallback:  fallback,
+		circuit:   NewCircuitBreaker(name, circuit),
+	}
+}
+
 // Action implements the Behaviour interface.
 func (bc *BehaviourCircuit) Action(addr Addr, msg Envelope) {
 	bc.circuit.Do(context.Background(), func(ctx context.Context) error {
@@ -187,3 +187,117 @@ func TestCircuitBreaker_FailureDueToError(t *testing.T) {
 	}, nil)
 	require.True(t, cb.IsOpened())
 }
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	var tripped error
+	cb := actorkit.NewCircuitBreaker("rates", actorkit.Circuit{
+		FailureRateThreshold: 50,
+		WindowSize:           4,
+		MinimumCalls:         4,
+		MinCoolDown:          time.Second,
+		MaxCoolDown:          2 * time.Second,
+		OnTrip: func(name string, lastError error) {
+			tripped = lastError
+		},
+	})
+
+	fail := func(ctx context.Context) error { return errors.New("bad") }
+	pass := func(ctx context.Context) error { return nil }
+
+	// not enough calls to evaluate rates.
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.NoError(t, cb.Do(context.Background(), pass, nil))
+	require.False(t, cb.IsOpened())
+
+	failureRate, _, calls := cb.Rates()
+	require.Equal(t, 3, calls)
+	require.InDelta(t, 66.6, failureRate, 0.1)
+
+	// fourth call reaches minimum calls with 50% failures.
+	require.NoError(t, cb.Do(context.Background(), pass, nil))
+	require.True(t, cb.IsOpened())
+	require.Equal(t, actorkit.ErrFailureRate, tripped)
+}
+
+func TestCircuitBreaker_FailureRateCountWindowSlides(t *testing.T) {
+	cb := actorkit.NewCircuitBreaker("rates", actorkit.Circuit{
+		FailureRateThreshold: 75,
+		WindowSize:           4,
+		MinimumCalls:         4,
+	})
+
+	fail := func(ctx context.Context) error { return errors.New("bad") }
+	pass := func(ctx context.Context) error { return nil }
+
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, cb.Do(context.Background(), pass, nil))
+	}
+
+	// earlier failures have slid out of the window.
+	failureRate, _, calls := cb.Rates()
+	require.Equal(t, 4, calls)
+	require.Equal(t, float64(0), failureRate)
+
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.False(t, cb.IsOpened())
+	require.Error(t, cb.Do(context.Background(), fail, nil))
+	require.True(t, cb.IsOpened())
+}
+
+func TestCircuitBreaker_SlowCallRateWithTimeWindow(t *testing.T) {
+	now := time.Now()
+	var tripped error
+	var closed bool
+
+	cb := actorkit.NewCircuitBreaker("slow", actorkit.Circuit{
+		SlowCallRateThreshold: 50,
+		SlowCallDuration:      100 * time.Millisecond,
+		WindowDuration:        10 * time.Second,
+		MinimumCalls:          2,
+		MinCoolDown:           time.Second,
+		MaxCoolDown:           2 * time.Second,
+		Now: func() time.Time {
+			return now
+		},
+		OnTrip: func(name string, lastError error) {
+			tripped = lastError
+		},
+		OnClose: func(name string, lastCoolDown time.Duration) {
+			closed = true
+		},
+	})
+
+	slow := func(ctx context.Context) error {
+		now = now.Add(200 * time.Millisecond)
+		return nil
+	}
+	fast := func(ctx context.Context) error { return nil }
+
+	require.NoError(t, cb.Do(context.Background(), slow, nil))
+
+	// slow call falls out of time window.
+	now = now.Add(11 * time.Second)
+	_, slowRate, calls := cb.Rates()
+	require.Equal(t, 0, calls)
+	require.Equal(t, float64(0), slowRate)
+
+	require.NoError(t, cb.Do(context.Background(), fast, nil))
+	require.False(t, cb.IsOpened())
+
+	require.NoError(t, cb.Do(context.Background(), slow, nil))
+	require.True(t, cb.IsOpened())
+	require.Equal(t, actorkit.ErrSlowCallRate, tripped)
+
+	// after cooldown a fast call closes the circuit and resets window.
+	now = now.Add(2 * time.Second)
+	require.NoError(t, cb.Do(context.Background(), fast, nil))
+	require.False(t, cb.IsOpened())
+	require.True(t, closed)
+
+	_, _, calls = cb.Rates()
+	require.Equal(t, 0, calls)
+}
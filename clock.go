@@ -0,0 +1,20 @@
+package actorkit
+
+import "time"
+
+var _ Clock = SystemClock{}
+
+// SystemClock implements the Clock interface using the system's
+// time through the time package.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls provided function in it's own goroutine after
+// provided duration has elapsed.
+func (SystemClock) AfterFunc(dur time.Duration, fn func()) Timer {
+	return time.AfterFunc(dur, fn)
+}
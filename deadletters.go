@@ -0,0 +1,302 @@
+package actorkit
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gokit/errors"
+)
+
+const defaultDeadLetterCapacity = 1000
+
+var _ DeadLetter = &DeadLetterOffice{}
+
+//*****************************************************************
+// DeadLetterOffice
+//*****************************************************************
+
+// DeadMailGroup defines a group of dead mails sharing the same target
+// address and message type.
+type DeadMailGroup struct {
+	Addr  string
+	Type  string
+	Mails []DeadMail
+}
+
+// DeadLetterOffice implements the DeadLetter interface, providing a bounded store
+// of the most recent dead mails, which can be queried, grouped by target address
+// and message type and re-delivered to a new or restarted address. It keeps a
+// count of all recorded dead mails per reason, including those evicted from
+// it's store.
+//
+// A DeadLetterOffice can be used directly as the Prop.DeadLetters of actors or be
+// attached to the package's deadletters event stream through DeadLetterOffice.Attach,
+// which receives all dead mails from actors using the default DeadLetter and the
+// address returned by DeadLetters().
+type DeadLetterOffice struct {
+	ml     sync.RWMutex
+	mails  []DeadMail
+	head   int
+	size   int
+	total  int64
+	counts map[DeadReason]int64
+
+	sl  sync.Mutex
+	sub Subscription
+}
+
+// NewDeadLetterOffice returns a new instance of DeadLetterOffice which stores
+// giving capacity of most recent dead mails. A default capacity is used if
+// provided is less than or equal to zero.
+func NewDeadLetterOffice(capacity int) *DeadLetterOffice {
+	if capacity <= 0 {
+		capacity = defaultDeadLetterCapacity
+	}
+
+	return &DeadLetterOffice{
+		mails:  make([]DeadMail, capacity),
+		counts: map[DeadReason]int64{},
+	}
+}
+
+// Attach subscribes DeadLetterOffice to the package's deadletters event stream,
+// recording all published dead mails. Attach does nothing if already attached.
+func (d *DeadLetterOffice) Attach() {
+	d.sl.Lock()
+	defer d.sl.Unlock()
+
+	if d.sub != nil {
+		return
+	}
+
+	d.sub = subscriber{deadLetters.Subscribe(func(event interface{}) {
+		if mail, ok := event.(DeadMail); ok {
+			d.RecoverMail(mail)
+		}
+	})}
+}
+
+// Detach ends DeadLetterOffice subscription to the package's deadletters event
+// stream.
+func (d *DeadLetterOffice) Detach() {
+	d.sl.Lock()
+	defer d.sl.Unlock()
+
+	if d.sub == nil {
+		return
+	}
+
+	d.sub.Stop()
+	d.sub = nil
+}
+
+// RecoverMail implements the DeadLetter interface, recording provided dead mail,
+// which evicts the oldest recorded dead mail if DeadLetterOffice is at capacity.
+func (d *DeadLetterOffice) RecoverMail(mail DeadMail) {
+	d.ml.Lock()
+	defer d.ml.Unlock()
+
+	d.total++
+	d.counts[mail.Reason]++
+
+	index := (d.head + d.size) % len(d.mails)
+	d.mails[index] = mail
+
+	if d.size < len(d.mails) {
+		d.size++
+		return
+	}
+
+	d.head = (d.head + 1) % len(d.mails)
+}
+
+// Len returns total dead mails currently stored.
+func (d *DeadLetterOffice) Len() int {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+	return d.size
+}
+
+// Total returns total dead mails ever recorded.
+func (d *DeadLetterOffice) Total() int64 {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+	return d.total
+}
+
+// Count returns total dead mails ever recorded for giving reason.
+func (d *DeadLetterOffice) Count(reason DeadReason) int64 {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+	return d.counts[reason]
+}
+
+// Counts returns a map of total dead mails ever recorded per reason.
+func (d *DeadLetterOffice) Counts() map[DeadReason]int64 {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+
+	counts := make(map[DeadReason]int64, len(d.counts))
+	for reason, count := range d.counts {
+		counts[reason] = count
+	}
+	return counts
+}
+
+// Recent returns at most giving count of the most recent dead mails, ordered
+// from oldest to newest. All stored dead mails are returned if count is
+// less than or equal to zero.
+func (d *DeadLetterOffice) Recent(count int) []DeadMail {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+
+	if count <= 0 || count > d.size {
+		count = d.size
+	}
+
+	mails := make([]DeadMail, 0, count)
+	for i := d.size - count; i < d.size; i++ {
+		mails = append(mails, d.mails[(d.head+i)%len(d.mails)])
+	}
+	return mails
+}
+
+// Query returns all stored dead mails for which provided function returns true,
+// ordered from oldest to newest.
+func (d *DeadLetterOffice) Query(fn func(DeadMail) bool) []DeadMail {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+
+	var mails []DeadMail
+	for i := 0; i < d.size; i++ {
+		mail := d.mails[(d.head+i)%len(d.mails)]
+		if fn(mail) {
+			mails = append(mails, mail)
+		}
+	}
+	return mails
+}
+
+// ByAddr returns all stored dead mails targeted at giving address string.
+func (d *DeadLetterOffice) ByAddr(addr string) []DeadMail {
+	return d.Query(func(mail DeadMail) bool {
+		return mailAddr(mail) == addr
+	})
+}
+
+// ByType returns all stored dead mails whose message data has the same type as
+// provided sample.
+func (d *DeadLetterOffice) ByType(sample interface{}) []DeadMail {
+	kind := fmt.Sprintf("%T", sample)
+	return d.Query(func(mail DeadMail) bool {
+		return mailType(mail) == kind
+	})
+}
+
+// ByReason returns all stored dead mails recorded for giving reason.
+func (d *DeadLetterOffice) ByReason(reason DeadReason) []DeadMail {
+	return d.Query(func(mail DeadMail) bool {
+		return mail.Reason == reason
+	})
+}
+
+// Groups returns all stored dead mails grouped by target address and message type,
+// ordered by the first occurrence of each group.
+func (d *DeadLetterOffice) Groups() []DeadMailGroup {
+	d.ml.RLock()
+	defer d.ml.RUnlock()
+
+	var groups []DeadMailGroup
+	indexes := map[string]int{}
+
+	for i := 0; i < d.size; i++ {
+		mail := d.mails[(d.head+i)%len(d.mails)]
+		addr, kind := mailAddr(mail), mailType(mail)
+
+		key := addr + "#" + kind
+		index, ok := indexes[key]
+		if !ok {
+			index = len(groups)
+			indexes[key] = index
+			groups = append(groups, DeadMailGroup{Addr: addr, Type: kind})
+		}
+
+		groups[index].Mails = append(groups[index].Mails, mail)
+	}
+	return groups
+}
+
+// Redeliver forwards all stored dead mails for which provided function returns true
+// to giving address, removing them from DeadLetterOffice. A nil function matches all
+// dead mails. It returns total mails re-delivered, stopping at the first failed
+// delivery, where the failed and remaining matching mails are kept, without
+// being recorded again by an address which uses the DeadLetterOffice.
+func (d *DeadLetterOffice) Redeliver(to Addr, fn func(DeadMail) bool) (int, error) {
+	d.ml.Lock()
+
+	var matched, kept []DeadMail
+	for i := 0; i < d.size; i++ {
+		mail := d.mails[(d.head+i)%len(d.mails)]
+		if fn == nil || fn(mail) {
+			matched = append(matched, mail)
+			continue
+		}
+		kept = append(kept, mail)
+	}
+
+	d.reset(kept)
+	d.ml.Unlock()
+
+	for index, mail := range matched {
+		if err := to.Forward(recoverable(mail.Message)); err != nil {
+			d.ml.Lock()
+			d.reset(append(d.all(), matched[index:]...))
+			d.ml.Unlock()
+
+			return index, errors.Wrap(err, "Failed to re-deliver dead mail to %q", to.Addr())
+		}
+	}
+	return len(matched), nil
+}
+
+// Clear removes all stored dead mails, counters are not reset.
+func (d *DeadLetterOffice) Clear() {
+	d.ml.Lock()
+	d.reset(nil)
+	d.ml.Unlock()
+}
+
+// all returns all stored dead mails, it expects lock to be held.
+func (d *DeadLetterOffice) all() []DeadMail {
+	mails := make([]DeadMail, 0, d.size)
+	for i := 0; i < d.size; i++ {
+		mails = append(mails, d.mails[(d.head+i)%len(d.mails)])
+	}
+	return mails
+}
+
+// reset replaces all stored dead mails with provided, keeping only the most
+// recent if above capacity, it expects lock to be held.
+func (d *DeadLetterOffice) reset(mails []DeadMail) {
+	if len(mails) > len(d.mails) {
+		mails = mails[len(mails)-len(d.mails):]
+	}
+
+	for i := range d.mails {
+		d.mails[i] = DeadMail{}
+	}
+
+	d.head = 0
+	d.size = copy(d.mails, mails)
+}
+
+func mailAddr(mail DeadMail) string {
+	if mail.To == nil {
+		return ""
+	}
+	return mail.To.Addr()
+}
+
+func mailType(mail DeadMail) string {
+	return fmt.Sprintf("%T", mail.Message.Data)
+}
@@ -0,0 +1,117 @@
+package actorkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterOfficeReasons(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	full := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{
+		Behaviour:   &basic{Message: make(chan *actorkit.Envelope, 1)},
+		Mailbox:     actorkit.BoundedBoxQueue(1, actorkit.DropNew, nil),
+		DeadLetters: office,
+	})
+
+	fullAddr := actorkit.AddressOf(full, "full")
+	require.NoError(t, fullAddr.Send(1, nil))
+	require.Error(t, fullAddr.Send(2, nil))
+	require.Equal(t, int64(1), office.Count(actorkit.MailboxFullReason))
+
+	stopped := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{
+		Behaviour:   &basic{Message: make(chan *actorkit.Envelope, 1)},
+		DeadLetters: office,
+	})
+	require.NoError(t, stopped.Start())
+	require.NoError(t, stopped.Kill())
+
+	stoppedAddr := actorkit.AddressOf(stopped, "stopped")
+	require.Error(t, stoppedAddr.Send("hello", nil))
+	require.Equal(t, int64(1), office.Count(actorkit.ActorStoppedReason))
+
+	require.Len(t, office.ByAddr(stoppedAddr.Addr()), 1)
+	require.Len(t, office.ByType(""), 1)
+	require.Len(t, office.ByType(0), 1)
+	require.Len(t, office.Groups(), 2)
+	require.Equal(t, int64(2), office.Total())
+}
+
+func TestDeadLetterOfficeFutureTimeout(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+	office.Attach()
+	defer office.Detach()
+
+	future := actorkit.TimedFuture(actorkit.DeadLetters(), 10*time.Millisecond)
+	require.Error(t, future.Wait())
+
+	require.Error(t, future.Send("late", nil))
+	require.Equal(t, int64(1), office.Count(actorkit.FutureTimeoutReason))
+
+	require.NoError(t, actorkit.DeadLetters().Send("lost", nil))
+	require.Equal(t, int64(1), office.Count(actorkit.DeadLettersReason))
+}
+
+func TestDeadLetterOfficeBoundedAndRedeliver(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(3)
+	deadAddr := actorkit.DeadLetters()
+
+	for i := 0; i < 5; i++ {
+		office.RecoverMail(actorkit.DeadMail{
+			To:      deadAddr,
+			Message: actorkit.CreateEnvelope(nil, actorkit.Header{}, i),
+			Reason:  actorkit.ActorStoppedReason,
+		})
+	}
+
+	require.Equal(t, 3, office.Len())
+	require.Equal(t, int64(5), office.Count(actorkit.ActorStoppedReason))
+
+	recent := office.Recent(2)
+	require.Len(t, recent, 2)
+	require.Equal(t, 3, recent[0].Message.Data)
+	require.Equal(t, 4, recent[1].Message.Data)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 3)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	delivered, err := office.Redeliver(actorkit.AddressOf(am, "replay"), func(mail actorkit.DeadMail) bool {
+		return mail.Message.Data.(int) >= 3
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, 1, office.Len())
+	require.Equal(t, 2, office.Recent(0)[0].Message.Data)
+
+	require.Equal(t, 3, (<-base.Message).Data)
+	require.Equal(t, 4, (<-base.Message).Data)
+}
+
+func TestDeadLetterOfficeRedeliverToStoppedActor(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: &basic{}, DeadLetters: office})
+	require.NoError(t, am.Start())
+	require.NoError(t, am.Kill())
+
+	addr := actorkit.AddressOf(am, "basic")
+	require.Error(t, addr.Send("first", nil))
+	require.Error(t, addr.Send("second", nil))
+	require.Equal(t, 2, office.Len())
+	require.Equal(t, int64(2), office.Total())
+
+	delivered, err := office.Redeliver(addr, nil)
+	require.Error(t, err)
+	require.Equal(t, 0, delivered)
+
+	// failed mails are restored once and not counted again.
+	require.Equal(t, 2, office.Len())
+	require.Equal(t, int64(2), office.Total())
+	require.Equal(t, "first", office.Recent(0)[0].Message.Data)
+	require.Equal(t, "second", office.Recent(0)[1].Message.Data)
+}
@@ -0,0 +1,83 @@
+package actorkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func expectTerminated(t *testing.T, base *basic) actorkit.Terminated {
+	select {
+	case env := <-base.Message:
+		terminated, ok := env.Data.(actorkit.Terminated)
+		require.True(t, ok, "expected terminated message but got %#v", env.Data)
+		return terminated
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "expected terminated message")
+	}
+	return actorkit.Terminated{}
+}
+
+func TestDeathWatchDeliversTerminated(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 2)}
+	watcher, err := system.Spawn("watcher", actorkit.Prop{Behaviour: base})
+	require.NoError(t, err)
+
+	group, err := system.Spawn("group", actorkit.Prop{Behaviour: &basic{Message: make(chan *actorkit.Envelope, 1)}})
+	require.NoError(t, err)
+
+	watched, err := group.Spawn("watched", actorkit.Prop{Behaviour: &basic{Message: make(chan *actorkit.Envelope, 1)}})
+	require.NoError(t, err)
+
+	require.NoError(t, watcher.DeathWatch(watched))
+
+	require.NoError(t, actorkit.Restart(watched))
+	require.Len(t, base.Message, 0)
+
+	require.NoError(t, actorkit.Kill(watched))
+
+	terminated := expectTerminated(t, base)
+	require.Equal(t, actorkit.KILLED, terminated.Signal)
+	require.Equal(t, watched.ID(), terminated.Addr.ID())
+	require.Equal(t, int64(1), terminated.Stat.Killed)
+	require.Equal(t, int64(1), terminated.Stat.Restarted)
+}
+
+func TestDeathWatchUnwatch(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 2)}
+	watcher, err := system.Spawn("watcher", actorkit.Prop{Behaviour: base})
+	require.NoError(t, err)
+
+	first, err := system.Spawn("first", actorkit.Prop{Behaviour: &basic{Message: make(chan *actorkit.Envelope, 1)}})
+	require.NoError(t, err)
+
+	second, err := system.Spawn("second", actorkit.Prop{Behaviour: &basic{Message: make(chan *actorkit.Envelope, 1)}})
+	require.NoError(t, err)
+
+	require.NoError(t, watcher.DeathWatch(first))
+	require.NoError(t, watcher.DeathWatch(second))
+	require.NoError(t, watcher.Unwatch(first))
+
+	require.NoError(t, actorkit.Destroy(first))
+	require.NoError(t, actorkit.Destroy(second))
+
+	terminated := expectTerminated(t, base)
+	require.Equal(t, actorkit.DESTROYED, terminated.Signal)
+	require.Equal(t, second.ID(), terminated.Addr.ID())
+
+	select {
+	case env := <-base.Message:
+		require.Fail(t, "unexpected message", "%#v", env.Data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
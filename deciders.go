@@ -0,0 +1,221 @@
+package actorkit
+
+import (
+	"reflect"
+
+	"github.com/gokit/errors"
+)
+
+// maxCauseDepth sets the maximum depth of a cause chain walked by deciders,
+// guarding against cyclic chains.
+const maxCauseDepth = 64
+
+//*****************************************************************
+// PartialDecider
+//*****************************************************************
+
+// PartialDecider defines a function which giving a value returns a directive
+// and true if it can decide for giving value, else false.
+type PartialDecider func(interface{}) (Directive, bool)
+
+// OrElse returns a Decider which uses the PartialDecider, falling back to provided
+// decider for values the PartialDecider can not decide for.
+func (pd PartialDecider) OrElse(fallback Decider) Decider {
+	return func(value interface{}) Directive {
+		if directive, ok := pd(value); ok {
+			return directive
+		}
+		return fallback(value)
+	}
+}
+
+// ComposePartialDeciders returns a PartialDecider which consults provided partial
+// deciders in order, returning the directive of the first which can decide.
+func ComposePartialDeciders(partials ...PartialDecider) PartialDecider {
+	return func(value interface{}) (Directive, bool) {
+		for _, partial := range partials {
+			if directive, ok := partial(value); ok {
+				return directive, true
+			}
+		}
+		return IgnoreDirective, false
+	}
+}
+
+// ComposeDeciders returns a Decider where provided child partial deciders are
+// consulted in order, before falling back to provided parent decider. It allows
+// a child actor's supervisor to override only the decisions it cares about.
+func ComposeDeciders(parent Decider, children ...PartialDecider) Decider {
+	return ComposePartialDeciders(children...).OrElse(parent)
+}
+
+//*****************************************************************
+// DeciderBuilder
+//*****************************************************************
+
+// DeciderBuilder builds a Decider from an ordered set of rules, where the first
+// matching rule decides the directive for a value, else the fallback decider or
+// default directive is used.
+//
+// Type and error rules match not only the value but also all causes wrapped by it,
+// which are walked through PanicEvent.Panic, EscalatedError, any error exposing
+// a Unwrap() error method and errors wrapped with the github.com/gokit/errors package.
+//
+//	decider := actorkit.NewDeciderBuilder().
+//		OnError(io.EOF, actorkit.StopDirective).
+//		OnType(&net.OpError{}, actorkit.RestartDirective).
+//		OnPanic(actorkit.RestartDirective).
+//		Default(actorkit.EscalateDirective).
+//		Build()
+type DeciderBuilder struct {
+	rules     []PartialDecider
+	fallback  Decider
+	directive Directive
+}
+
+// NewDeciderBuilder returns a new DeciderBuilder, which defaults to the
+// EscalateDirective for values matching no rule.
+func NewDeciderBuilder() *DeciderBuilder {
+	return &DeciderBuilder{directive: EscalateDirective}
+}
+
+// OnType adds a rule returning provided directive for values which are or wrap
+// a value of the same type as provided sample.
+func (b *DeciderBuilder) OnType(sample interface{}, directive Directive) *DeciderBuilder {
+	target := reflect.TypeOf(sample)
+	return b.With(func(value interface{}) (Directive, bool) {
+		found := eachCause(value, func(cause interface{}) bool {
+			return reflect.TypeOf(cause) == target
+		})
+		return directive, found
+	})
+}
+
+// OnError adds a rule returning provided directive for values which are or wrap
+// provided error.
+func (b *DeciderBuilder) OnError(target error, directive Directive) *DeciderBuilder {
+	return b.With(func(value interface{}) (Directive, bool) {
+		found := eachCause(value, func(cause interface{}) bool {
+			return equalValues(cause, target)
+		})
+		return directive, found
+	})
+}
+
+// OnPanic adds a rule returning provided directive for PanicEvent values.
+func (b *DeciderBuilder) OnPanic(directive Directive) *DeciderBuilder {
+	return b.With(func(value interface{}) (Directive, bool) {
+		switch value.(type) {
+		case PanicEvent, *PanicEvent:
+			return directive, true
+		}
+		return directive, false
+	})
+}
+
+// When adds a rule returning provided directive for values matching provided
+// predicate.
+func (b *DeciderBuilder) When(predicate func(interface{}) bool, directive Directive) *DeciderBuilder {
+	return b.With(func(value interface{}) (Directive, bool) {
+		return directive, predicate(value)
+	})
+}
+
+// With adds provided PartialDecider as a rule.
+func (b *DeciderBuilder) With(partial PartialDecider) *DeciderBuilder {
+	b.rules = append(b.rules, partial)
+	return b
+}
+
+// Default sets the directive returned for values matching no rule.
+func (b *DeciderBuilder) Default(directive Directive) *DeciderBuilder {
+	b.directive = directive
+	b.fallback = nil
+	return b
+}
+
+// Fallback sets the decider used for values matching no rule, which takes
+// precedence over the default directive. It allows a child's rules to extend
+// those of it's parent.
+func (b *DeciderBuilder) Fallback(decider Decider) *DeciderBuilder {
+	b.fallback = decider
+	return b
+}
+
+// Partial returns a PartialDecider consulting all rules of the builder, without
+// the fallback decider or default directive.
+func (b *DeciderBuilder) Partial() PartialDecider {
+	return ComposePartialDeciders(append([]PartialDecider(nil), b.rules...)...)
+}
+
+// Build returns the Decider of the builder.
+func (b *DeciderBuilder) Build() Decider {
+	fallback := b.fallback
+	if fallback == nil {
+		directive := b.directive
+		fallback = func(_ interface{}) Directive {
+			return directive
+		}
+	}
+	return b.Partial().OrElse(fallback)
+}
+
+//*****************************************************************
+// internal functions
+//*****************************************************************
+
+// eachCause calls provided function for giving value and all causes wrapped by
+// it, till function returns true, in which case eachCause returns true.
+func eachCause(value interface{}, fn func(interface{}) bool) bool {
+	for depth := 0; value != nil && depth < maxCauseDepth; depth++ {
+		if fn(value) {
+			return true
+		}
+
+		switch item := value.(type) {
+		case PanicEvent:
+			value = item.Panic
+		case *PanicEvent:
+			value = item.Panic
+		case EscalatedError:
+			if item.Err == nil {
+				value = item.Value
+				continue
+			}
+			if eachCause(item.Err, fn) {
+				return true
+			}
+			value = item.Value
+		case interface{ Unwrap() error }:
+			value = nilIfNil(item.Unwrap())
+		case *errors.PointingError:
+			if item.Parent == nil {
+				return false
+			}
+			value = item.Parent
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// nilIfNil returns an untyped nil for nil errors.
+func nilIfNil(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// equalValues returns true/false if both values are equal, guarding against
+// incomparable types.
+func equalValues(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,85 @@
+package actorkit_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	kerrors "github.com/gokit/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+)
+
+type timeoutError struct {
+	op string
+}
+
+func (t *timeoutError) Error() string {
+	return t.op + " timed out"
+}
+
+type wrapped struct {
+	err error
+}
+
+func (w wrapped) Error() string {
+	return "wrapped: " + w.err.Error()
+}
+
+func (w wrapped) Unwrap() error {
+	return w.err
+}
+
+func TestDeciderBuilder(t *testing.T) {
+	decider := actorkit.NewDeciderBuilder().
+		OnError(io.EOF, actorkit.StopDirective).
+		OnType(&timeoutError{}, actorkit.RestartDirective).
+		OnPanic(actorkit.KillDirective).
+		When(func(value interface{}) bool {
+			return value == "ignore me"
+		}, actorkit.IgnoreDirective).
+		Build()
+
+	require.Equal(t, actorkit.StopDirective, decider(io.EOF))
+	require.Equal(t, actorkit.StopDirective, decider(wrapped{err: io.EOF}))
+	require.Equal(t, actorkit.StopDirective, decider(kerrors.Wrap(io.EOF, "read failed")))
+	require.Equal(t, actorkit.StopDirective, decider(actorkit.EscalatedError{Err: wrapped{err: io.EOF}}))
+
+	require.Equal(t, actorkit.RestartDirective, decider(&timeoutError{op: "dial"}))
+	require.Equal(t, actorkit.RestartDirective, decider(wrapped{err: &timeoutError{op: "dial"}}))
+
+	// earlier rules win, even for panics wrapping known errors.
+	require.Equal(t, actorkit.StopDirective, decider(actorkit.PanicEvent{Panic: io.EOF}))
+	require.Equal(t, actorkit.KillDirective, decider(actorkit.PanicEvent{Panic: "boom"}))
+
+	require.Equal(t, actorkit.IgnoreDirective, decider("ignore me"))
+	require.Equal(t, actorkit.EscalateDirective, decider(errors.New("unknown")))
+	require.Equal(t, actorkit.EscalateDirective, decider([]int{1}))
+}
+
+func TestComposeDeciders(t *testing.T) {
+	parent := actorkit.NewDeciderBuilder().
+		OnError(io.EOF, actorkit.StopDirective).
+		OnPanic(actorkit.RestartDirective).
+		Default(actorkit.DestroyDirective).
+		Build()
+
+	child := actorkit.NewDeciderBuilder().
+		OnPanic(actorkit.KillDirective).
+		Partial()
+
+	decider := actorkit.ComposeDeciders(parent, child)
+	require.Equal(t, actorkit.KillDirective, decider(actorkit.PanicEvent{Panic: "boom"}))
+	require.Equal(t, actorkit.StopDirective, decider(io.EOF))
+	require.Equal(t, actorkit.DestroyDirective, decider(errors.New("unknown")))
+
+	extended := actorkit.NewDeciderBuilder().
+		OnType(&timeoutError{}, actorkit.RestartDirective).
+		Fallback(parent).
+		Build()
+
+	require.Equal(t, actorkit.RestartDirective, extended(&timeoutError{op: "dial"}))
+	require.Equal(t, actorkit.StopDirective, extended(io.EOF))
+	require.Equal(t, actorkit.DestroyDirective, extended("unknown"))
+}
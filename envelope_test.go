@@ -0,0 +1,96 @@
+package actorkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeWithContext(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+
+	header := actorkit.Header{"a": "b"}
+	env := actorkit.CreateEnvelope(nil, header, 1).WithContext(ctx)
+	require.False(t, header.Has(actorkit.DeadlineHeader))
+	require.True(t, env.Has(actorkit.DeadlineHeader))
+	require.Equal(t, "b", env.Get("a"))
+	require.Equal(t, ctx, env.Context())
+
+	got, ok := env.Deadline()
+	require.True(t, ok)
+	require.True(t, got.Equal(deadline))
+
+	require.False(t, env.Expired(time.Now()))
+	require.True(t, env.Expired(deadline))
+
+	cancel()
+	require.True(t, env.Expired(time.Now()))
+
+	plain := actorkit.CreateEnvelope(nil, actorkit.Header{}, 1)
+	require.Equal(t, context.Background(), plain.Context())
+	require.False(t, plain.Expired(time.Now()))
+}
+
+func TestActorDeadLettersExpiredEnvelopes(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+	base := &basic{Message: make(chan *actorkit.Envelope, 2)}
+
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base, DeadLetters: office})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	addr := actorkit.AddressOf(am, "basic")
+
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	require.NoError(t, actorkit.SendContext(expired, addr, 1, nil))
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	require.NoError(t, actorkit.SendContext(cancelled, addr, 2, nil))
+
+	header := actorkit.Header{actorkit.DeadlineHeader: time.Now().Add(-time.Second).Format(time.RFC3339Nano)}
+	require.NoError(t, addr.SendWithHeader(3, header, nil))
+
+	require.NoError(t, addr.Send(4, nil))
+
+	content := <-base.Message
+	require.Equal(t, 4, content.Data)
+	require.Equal(t, int64(3), office.Count(actorkit.DeadlineExceededReason))
+}
+
+func TestContextPropagatesThroughRouter(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 2)}
+	routee, err := system.Spawn("routee", actorkit.Prop{Behaviour: base})
+	require.NoError(t, err)
+
+	router, err := system.Spawn("router", actorkit.Prop{Behaviour: actorkit.NewRoundRobinRouter(routee)})
+	require.NoError(t, err)
+
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	require.NoError(t, actorkit.SendContext(ctx, router, 1, nil))
+
+	content := <-base.Message
+	got, ok := content.Context().Deadline()
+	require.True(t, ok)
+	require.True(t, got.Equal(deadline))
+
+	header := actorkit.Header{actorkit.DeadlineHeader: deadline.Format(time.RFC3339Nano)}
+	require.NoError(t, router.SendWithHeader(2, header, nil))
+
+	content = <-base.Message
+	got, ok = content.Context().Deadline()
+	require.True(t, ok)
+	require.True(t, got.Equal(deadline))
+}
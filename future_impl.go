@@ -27,47 +27,56 @@ var (
 type FutureImpl struct {
 	id     xid.ID
 	parent Addr
-	timer  *time.Timer
+	timer  Timer
+	clock  Clock
 	events *es.EventStream
 
 	ac    sync.Mutex
 	pipes []func(Envelope)
 
-	w      sync.WaitGroup
-	cw     sync.Mutex
-	err    error
-	result *Envelope
+	w        sync.WaitGroup
+	cw       sync.Mutex
+	err      error
+	timedOut bool
+	result   *Envelope
 }
 
 // NewFuture returns a new instance of giving future.
 func NewFuture(parent Addr) *FutureImpl {
 	var ft FutureImpl
 	ft.parent = parent
+	ft.clock = clockOf(parent)
 	ft.events = es.New()
 	ft.w.Add(1)
 	return &ft
 }
 
 // TimedFuture returns a new instance of giving future.
+//
+// The future's timeout is scheduled using the Clock of the parent's
+// actor if available, else the SystemClock is used.
 func TimedFuture(parent Addr, dur time.Duration) *FutureImpl {
 	var ft FutureImpl
 	ft.parent = parent
+	ft.clock = clockOf(parent)
 	ft.events = es.New()
-	ft.timer = time.NewTimer(dur)
 	ft.w.Add(1)
 
-	go ft.timedResolved()
+	timer := ft.clock.AfterFunc(dur, ft.timedResolved)
+
+	ft.cw.Lock()
+	ft.timer = timer
+	ft.cw.Unlock()
 	return &ft
 }
 
 // Forward delivers giving envelope into Future actor which if giving
 // future is not yet resolved will be the resolution of future.
 func (f *FutureImpl) Forward(reply Envelope) error {
-	if f.resolved() {
-		return errors.Wrap(ErrFutureResolved, "Future %q already resolved", f.Addr())
+	if !f.resolve(reply) {
+		return f.rejectReply(reply)
 	}
 
-	f.Resolve(reply)
 	f.broadcast()
 	return nil
 }
@@ -77,13 +86,7 @@ func (f *FutureImpl) Forward(reply Envelope) error {
 // If data is a type of error then the giving future is
 // rejected.
 func (f *FutureImpl) Send(data interface{}, addr Addr) error {
-	if f.resolved() {
-		return errors.Wrap(ErrFutureResolved, "Future %q already resolved", f.Addr())
-	}
-
-	f.Resolve(CreateEnvelope(addr, Header{}, data))
-	f.broadcast()
-	return nil
+	return f.Forward(CreateEnvelope(addr, Header{}, data))
 }
 
 // SendWithHeader delivers giving data to Future as the resolution of
@@ -93,13 +96,7 @@ func (f *FutureImpl) Send(data interface{}, addr Addr) error {
 // If data is a type of error then the giving future is
 // rejected.
 func (f *FutureImpl) SendWithHeader(data interface{}, h Header, addr Addr) error {
-	if f.resolved() {
-		return errors.Wrap(ErrFutureResolved, "Future %q already resolved", f.Addr())
-	}
-
-	f.Resolve(CreateEnvelope(addr, h, data))
-	f.broadcast()
-	return nil
+	return f.Forward(CreateEnvelope(addr, h, data))
 }
 
 // Escalate escalates giving value into the parent of giving future, which also fails future
@@ -113,8 +110,9 @@ func (f *FutureImpl) Escalate(m interface{}) {
 		data.Err = merr
 	}
 
-	f.Resolve(CreateEnvelope(DeadLetters(), Header{}, data))
-	f.broadcast()
+	if f.resolve(CreateEnvelope(DeadLetters(), Header{}, data)) {
+		f.broadcast()
+	}
 }
 
 // AddressOf requests giving service from future's parent AddressOf method.
@@ -153,6 +151,11 @@ func (f *FutureImpl) DeathWatch(addr Addr) error {
 	return errors.WrapOnly(ErrHasNoActor)
 }
 
+// Unwatch implements DeathWatch interface.
+func (f *FutureImpl) Unwatch(addr Addr) error {
+	return errors.WrapOnly(ErrHasNoActor)
+}
+
 // Parent returns the address of the parent of giving Future.
 func (f *FutureImpl) Parent() Addr {
 	return f.parent
@@ -230,27 +233,23 @@ func (f *FutureImpl) Wait() error {
 // PipeAction allows the addition of functions to be called with result of
 // future.
 func (f *FutureImpl) PipeAction(actions ...func(envelope Envelope)) {
-	if f.resolved() {
-		for _, action := range actions {
-			action(*f.result)
-		}
+	// checked under lock, as pipes added after a broadcast are never called.
+	f.ac.Lock()
+	if !f.resolved() {
+		f.pipes = append(f.pipes, actions...)
+		f.ac.Unlock()
 		return
 	}
-
-	f.ac.Lock()
-	f.pipes = append(f.pipes, actions...)
 	f.ac.Unlock()
+
+	result := f.Result()
+	for _, action := range actions {
+		action(result)
+	}
 }
 
 // Pipe adds giving set of address into giving Future.
 func (f *FutureImpl) Pipe(addrs ...Addr) {
-	if f.resolved() {
-		for _, addr := range addrs {
-			addr.Forward(*f.result)
-		}
-		return
-	}
-
 	for _, addr := range addrs {
 		func(a Addr) {
 			f.PipeAction(func(msg Envelope) {
@@ -279,29 +278,36 @@ func (f *FutureImpl) Result() Envelope {
 
 // Resolve resolves giving future with envelope.
 func (f *FutureImpl) Resolve(env Envelope) {
-	if f.resolved() {
-		return
-	}
-
-	var ok bool
-	var err error
-	var rejected bool
+	f.resolve(env)
+}
 
+// resolve resolves future with envelope, returning false if future was
+// already resolved. Only the first of concurrent resolutions succeeds.
+func (f *FutureImpl) resolve(env Envelope) bool {
 	f.cw.Lock()
-	if err, ok = env.Data.(error); ok {
-		rejected = true
+	if f.result != nil || f.err != nil {
+		f.cw.Unlock()
+		return false
+	}
+
+	err, rejected := env.Data.(error)
+	if rejected {
 		f.err = err
 	}
 
 	f.result = &env
+	if f.timer != nil {
+		f.timer.Stop()
+	}
 	f.cw.Unlock()
 	f.w.Done()
 
 	if rejected {
 		f.events.Publish(FutureRejected{Err: err, ID: f.id.String()})
-		return
+		return true
 	}
 	f.events.Publish(FutureResolved{Data: env, ID: f.id.String()})
+	return true
 }
 
 func (f *FutureImpl) resolved() bool {
@@ -323,23 +329,56 @@ func (f *FutureImpl) fulfilled() bool {
 }
 
 func (f *FutureImpl) broadcast() {
-	var res *Envelope
+	var pipes []func(Envelope)
 
 	f.ac.Lock()
-	res = f.result
+	pipes = f.pipes
+	f.pipes = nil
 	f.ac.Unlock()
 
-	for _, action := range f.pipes {
-		action(*res)
+	result := f.Result()
+	for _, action := range pipes {
+		action(result)
 	}
-
-	f.pipes = nil
 }
 
 func (f *FutureImpl) timedResolved() {
-	<-f.timer.C
 	if f.resolved() {
 		return
 	}
+
+	f.cw.Lock()
+	f.timedOut = true
+	f.cw.Unlock()
+
 	f.Escalate(ErrFutureTimeout)
 }
+
+// rejectReply returns an error for a reply delivered to an already resolved
+// future, where the reply is sent to the deadletters if future timed out.
+func (f *FutureImpl) rejectReply(reply Envelope) error {
+	f.cw.Lock()
+	timedOut := f.timedOut
+	f.cw.Unlock()
+
+	if timedOut {
+		eventDeathMails.RecoverMail(DeadMail{
+			To:      f,
+			Message: reply,
+			Reason:  FutureTimeoutReason,
+			Time:    f.clock.Now(),
+		})
+	}
+	return errors.Wrap(ErrFutureResolved, "Future %q already resolved", f.Addr())
+}
+
+// clockOf returns the Clock of the actor of giving address if any, else
+// the SystemClock.
+func clockOf(addr Addr) Clock {
+	if addr != nil {
+		if ac := addr.Actor(); ac != nil {
+			return ac.Clock()
+		}
+	}
+	return SystemClock{}
+}
@@ -2,6 +2,8 @@ package actorkit_test
 
 import (
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -53,3 +55,37 @@ func TestFutureTimeout(t *testing.T) {
 	require.Equal(t, newFuture.Err().Error(), actorkit.ErrFutureTimeout.Error())
 	require.Error(t, newFuture.Send("ready", eb))
 }
+
+func TestFutureConcurrentReplyAndCancel(t *testing.T) {
+	addr := &mocks.AddrImpl{}
+
+	for i := 0; i < 100; i++ {
+		future := actorkit.NewFuture(addr)
+
+		var piped int32
+		future.PipeAction(func(actorkit.Envelope) {
+			atomic.AddInt32(&piped, 1)
+		})
+
+		var accepted int32
+		var waiter sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			waiter.Add(2)
+			go func() {
+				defer waiter.Done()
+				if future.Send("reply", eb) == nil {
+					atomic.AddInt32(&accepted, 1)
+				}
+			}()
+			go func() {
+				defer waiter.Done()
+				future.Escalate(errors.New("cancelled"))
+			}()
+		}
+		waiter.Wait()
+
+		future.Wait()
+		require.True(t, atomic.LoadInt32(&accepted) <= 1)
+		require.Equal(t, int32(1), atomic.LoadInt32(&piped))
+	}
+}
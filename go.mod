@@ -36,7 +36,7 @@ require (
 	github.com/segmentio/kafka-go v0.2.2
 	github.com/serialx/hashring v0.0.0-20180504054112-49a4782e9908
 	github.com/stretchr/testify v1.2.2
-	go.opencensus.io v0.18.0 // indirect
+	go.opencensus.io v0.18.0
 	golang.org/x/crypto v0.0.0-20181203042331-505ab145d0a9 // indirect
 	golang.org/x/sync v0.0.0-20181108010431-42b317875d0f
 	google.golang.org/api v0.0.0-20181126234655-bed42c95df7d
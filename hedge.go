@@ -0,0 +1,226 @@
+package actorkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gokit/errors"
+)
+
+// errors ...
+var (
+	// ErrHedgeCancelled is used to reject the futures of hedged requests which
+	// lost to another request.
+	ErrHedgeCancelled = errors.New("Hedged request cancelled")
+
+	// ErrHedgeNoAddr is used to reject a hedged request with no address.
+	ErrHedgeNoAddr = errors.New("Hedged request has no address")
+)
+
+//***********************************************************
+// Hedge
+//***********************************************************
+
+// Hedge delivers data with attached headers to the first of provided addresses
+// using a timed Future as sender, and if no reply arrives within provided delay
+// or the request fails, delivers the same request to the next address, till
+// all addresses are exhausted.
+//
+// The returned Future is resolved with the first successful reply, whereupon
+// the futures of all other requests are rejected with ErrHedgeCancelled,
+// causing their late replies to be rejected. If all requests fail, the
+// returned Future is rejected with the error of the last failed request.
+//
+// Every request times out after provided timeout unless it is zero, and delays
+// are scheduled using the Clock of the first address.
+func Hedge(data interface{}, h Header, delay time.Duration, timeout time.Duration, addrs ...Addr) Future {
+	return HedgeContext(context.Background(), data, h, delay, timeout, addrs...)
+}
+
+// HedgeContext works like Hedge, where provided context is attached to every
+// request, and once it is done no further request is delivered, rejecting
+// the returned Future with the context's error.
+func HedgeContext(ctx context.Context, data interface{}, h Header, delay time.Duration, timeout time.Duration, addrs ...Addr) Future {
+	if len(addrs) == 0 {
+		result := NewFuture(DeadLetters())
+		result.Escalate(ErrHedgeNoAddr)
+		return result
+	}
+
+	result := NewFuture(addrs[0])
+	hedgeTo(result, CreateEnvelope(result, h, data).WithContext(ctx), delay, timeout, addrs)
+	return result
+}
+
+// Hedge delivers a hedged request to the addresses of set, as described by
+// the Hedge function.
+func (ad *ServiceSet) Hedge(data interface{}, h Header, delay time.Duration, timeout time.Duration) Future {
+	return Hedge(data, h, delay, timeout, ad.addrs...)
+}
+
+// hedgeTo delivers copies of envelope as hedged requests to provided addresses,
+// resolving result with it's outcome. If result is resolved or rejected elsewhere
+// before then, e.g by a timeout, or the envelope's context is done, all pending
+// requests are cancelled.
+func hedgeTo(result *FutureImpl, env Envelope, delay time.Duration, timeout time.Duration, addrs []Addr) {
+	hd := newHedge(result, env, timeout, addrs)
+	hd.launch()
+
+	go hd.run(result, delay, 1)
+}
+
+// scatterTo delivers copies of envelope to all provided addresses at once,
+// resolving result with the first successful reply, else rejecting it with the
+// error of the last failed request. If result is resolved or rejected elsewhere
+// before then, e.g by a timeout, or the envelope's context is done, all pending
+// requests are cancelled.
+func scatterTo(result *FutureImpl, env Envelope, timeout time.Duration, addrs []Addr) {
+	hd := newHedge(result, env, timeout, addrs)
+	for range hd.addrs {
+		hd.launch()
+	}
+
+	go hd.run(result, 0, len(hd.addrs))
+}
+
+// newHedge returns a new hedge for provided addresses, which is stopped
+// once result is resolved or rejected.
+func newHedge(result *FutureImpl, env Envelope, timeout time.Duration, addrs []Addr) *hedge {
+	targets := make([]Addr, len(addrs))
+	copy(targets, addrs)
+
+	hd := &hedge{
+		env:     env,
+		timeout: timeout,
+		addrs:   targets,
+		clock:   clockOf(targets[0]),
+		done:    make(chan *FutureImpl, len(targets)),
+		stopped: make(chan struct{}),
+	}
+
+	var once sync.Once
+	result.PipeAction(func(Envelope) {
+		once.Do(func() {
+			close(hd.stopped)
+		})
+	})
+
+	return hd
+}
+
+// hedge holds the state of a hedged request.
+type hedge struct {
+	env     Envelope
+	timeout time.Duration
+	addrs   []Addr
+	clock   Clock
+
+	next    int
+	pending []*FutureImpl
+	done    chan *FutureImpl
+	stopped chan struct{}
+}
+
+// run awaits the outstanding requests already launched, launching requests
+// to remaining addresses after every delay or failed request.
+func (hd *hedge) run(result *FutureImpl, delay time.Duration, outstanding int) {
+	var lastErr error
+	for outstanding > 0 {
+		var timer Timer
+		var elapsed chan struct{}
+		if hd.next < len(hd.addrs) {
+			elapsed = make(chan struct{})
+			timer = hd.clock.AfterFunc(delay, func() {
+				close(elapsed)
+			})
+		}
+
+		select {
+		case <-hd.stopped:
+			if timer != nil {
+				timer.Stop()
+			}
+
+			hd.cancel(nil)
+			return
+		case <-hd.env.Context().Done():
+			if timer != nil {
+				timer.Stop()
+			}
+
+			hd.cancel(nil)
+			result.Escalate(hd.env.Context().Err())
+			return
+		case <-elapsed:
+			hd.launch()
+			outstanding++
+		case attempt := <-hd.done:
+			outstanding--
+
+			if err := attempt.Err(); err == nil {
+				if timer != nil {
+					timer.Stop()
+				}
+
+				hd.cancel(attempt)
+				result.Forward(attempt.Result())
+				return
+			}
+
+			lastErr = attempt.Err()
+			if hd.next < len(hd.addrs) {
+				hd.launch()
+				outstanding++
+			}
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	result.Escalate(lastErr)
+}
+
+// launch delivers a copy of envelope to the next address, with a new Future
+// as it's sender. No copy is delivered once the envelope's context is done.
+func (hd *hedge) launch() {
+	addr := hd.addrs[hd.next]
+	hd.next++
+
+	attempt := requestFuture(addr, hd.timeout)
+	hd.pending = append(hd.pending, attempt)
+
+	request := hd.env
+	request.Sender = attempt
+
+	if err := request.Context().Err(); err != nil {
+		attempt.Escalate(err)
+	} else if err := addr.Forward(request); err != nil {
+		attempt.Escalate(err)
+	}
+
+	go func() {
+		attempt.Wait()
+		hd.done <- attempt
+	}()
+}
+
+// cancel rejects all pending requests except the winner, if any.
+func (hd *hedge) cancel(winner *FutureImpl) {
+	for _, attempt := range hd.pending {
+		if attempt != winner {
+			attempt.Escalate(ErrHedgeCancelled)
+		}
+	}
+}
+
+// requestFuture returns a Future for a request to provided address, which
+// times out after provided timeout unless it is zero.
+func requestFuture(addr Addr, timeout time.Duration) *FutureImpl {
+	if timeout <= 0 {
+		return NewFuture(addr)
+	}
+	return TimedFuture(addr, timeout)
+}
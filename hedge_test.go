@@ -0,0 +1,91 @@
+package actorkit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func replier(t *testing.T, name string, delay time.Duration, replies chan error) actorkit.Addr {
+	am := actorkit.FromFunc("ns", name, func(addr actorkit.Addr, env actorkit.Envelope) {
+		time.Sleep(delay)
+		err := env.Sender.Send(name, addr)
+		if replies != nil {
+			replies <- err
+		}
+	})
+	require.NoError(t, am.Start())
+	return actorkit.AddressOf(am, name)
+}
+
+func TestHedgeTakesFirstReply(t *testing.T) {
+	slowReplies := make(chan error, 1)
+	slow := replier(t, "slow", 100*time.Millisecond, slowReplies)
+	fast := replier(t, "fast", 0, nil)
+	defer actorkit.Destroy(slow)
+	defer actorkit.Destroy(fast)
+
+	start := time.Now()
+	future := actorkit.Hedge("ping", actorkit.Header{}, 20*time.Millisecond, time.Second, slow, fast)
+	require.NoError(t, future.Wait())
+	require.Equal(t, "fast", future.Result().Data)
+	require.True(t, time.Since(start) < 100*time.Millisecond)
+
+	// late reply of cancelled request is rejected.
+	require.Error(t, <-slowReplies)
+}
+
+func TestHedgeMovesOnFailures(t *testing.T) {
+	dead := actorkit.NewActorImpl("ns", "dead", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, dead.Start())
+	require.NoError(t, dead.Kill())
+	deadAddr := actorkit.AddressOf(dead, "dead")
+
+	fast := replier(t, "fast", 0, nil)
+	defer actorkit.Destroy(fast)
+
+	future := actorkit.Hedge("ping", actorkit.Header{}, time.Hour, time.Second, deadAddr, fast)
+	require.NoError(t, future.Wait())
+	require.Equal(t, "fast", future.Result().Data)
+
+	failed := actorkit.Hedge("ping", actorkit.Header{}, time.Hour, time.Second, deadAddr)
+	require.Error(t, failed.Wait())
+
+	require.Error(t, actorkit.Hedge("ping", actorkit.Header{}, time.Hour, time.Second).Wait())
+}
+
+func TestServiceSetHedge(t *testing.T) {
+	slow := replier(t, "slow", 100*time.Millisecond, nil)
+	fast := replier(t, "fast", 0, nil)
+	defer actorkit.Destroy(slow)
+	defer actorkit.Destroy(fast)
+
+	set := actorkit.NewServiceSet()
+	set.Add(slow)
+	set.Add(fast)
+
+	future := set.Hedge("ping", actorkit.Header{}, 10*time.Millisecond, time.Second)
+	require.NoError(t, future.Wait())
+	require.Equal(t, "fast", future.Result().Data)
+}
+
+func TestHedgeContextStopsOnCancel(t *testing.T) {
+	replies := make(chan error, 2)
+	slow := replier(t, "slow", 0, replies)
+	fast := replier(t, "fast", 0, replies)
+	defer actorkit.Destroy(slow)
+	defer actorkit.Destroy(fast)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	future := actorkit.HedgeContext(ctx, "ping", actorkit.Header{}, time.Millisecond, time.Second, slow, fast)
+	require.Error(t, future.Wait())
+
+	// no request is delivered for a done context.
+	time.Sleep(20 * time.Millisecond)
+	require.Len(t, replies, 0)
+}
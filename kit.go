@@ -1,6 +1,7 @@
 package actorkit
 
 import (
+	"context"
 	"time"
 
 	"github.com/gokit/es"
@@ -11,6 +12,11 @@ import (
 const (
 	stackSize = 1 << 16
 
+	// DeadlineHeader defines the header key used by an Envelope to carry
+	// it's deadline in RFC3339Nano format, allowing it's deadline to be
+	// retained across serialization and network hops.
+	DeadlineHeader = "X-Actorkit-Deadline"
+
 	// PackageName defines the name for the package used in relationship
 	// for messages or different types.
 	PackageName = "actorkit"
@@ -22,11 +28,71 @@ var (
 	eventDeathMails = NewEventDeathMail(deadLetters)
 )
 
+// DeadReason defines a type to represent the reason a mail became a dead mail.
+type DeadReason uint8
+
+// constants of dead mail reasons.
+const (
+	// UnknownReason is used for dead mails with no known reason.
+	UnknownReason DeadReason = iota
+
+	// DeadLettersReason is used for mails directly sent to the deadletters address.
+	DeadLettersReason
+
+	// ActorStoppedReason is used for mails which could not be processed because
+	// target actor was stopped, killed or destroyed.
+	ActorStoppedReason
+
+	// MailboxFullReason is used for mails which were rejected by the mailbox
+	// of target actor.
+	MailboxFullReason
+
+	// FutureTimeoutReason is used for mails delivered to a future which had
+	// already timed out.
+	FutureTimeoutReason
+
+	// DeadlineExceededReason is used for mails whose deadline passed or whose
+	// context was cancelled before they were processed.
+	DeadlineExceededReason
+
+	// RateLimitedReason is used for mails which exceeded the rate limit of
+	// their target address.
+	RateLimitedReason
+
+	// RetriesExhaustedReason is used for mails whose delivery failed after
+	// all retry attempts.
+	RetriesExhaustedReason
+)
+
+// String returns a text version of the reason.
+func (d DeadReason) String() string {
+	switch d {
+	case DeadLettersReason:
+		return "DEADLETTERS"
+	case ActorStoppedReason:
+		return "ACTOR_STOPPED"
+	case MailboxFullReason:
+		return "MAILBOX_FULL"
+	case FutureTimeoutReason:
+		return "FUTURE_TIMEOUT"
+	case DeadlineExceededReason:
+		return "DEADLINE_EXCEEDED"
+	case RateLimitedReason:
+		return "RATE_LIMITED"
+	case RetriesExhaustedReason:
+		return "RETRIES_EXHAUSTED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 // DeadMail defines the type of event triggered by the deadletters
 // event pipeline.
 type DeadMail struct {
 	To      Addr
 	Message Envelope
+	Reason  DeadReason
+	Time    time.Time
 }
 
 //***************************************************************************
@@ -73,6 +139,78 @@ type Envelope struct {
 	Sender Addr
 	Ref    xid.ID
 	Data   interface{}
+
+	// ctx is the context of envelope, which is a derivedContext if created
+	// from the DeadlineHeader by the processing actor, whose cancellation
+	// is not propagated.
+	ctx context.Context
+
+	// recovered marks an envelope whose sender delivers it to the dead letters
+	// if not accepted, hence a receiver rejecting it must not.
+	recovered bool
+}
+
+// derivedContext wraps a context created by an actor from an envelope's
+// DeadlineHeader.
+type derivedContext struct {
+	context.Context
+}
+
+// Context returns the context attached to envelope, else a background context.
+func (e Envelope) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// WithContext returns a copy of envelope using provided context, where the deadline
+// of the context if any, is also set into a copy of envelope's header with
+// DeadlineHeader as key.
+func (e Envelope) WithContext(ctx context.Context) Envelope {
+	header := make(Header, len(e.Header)+1)
+	for k, v := range e.Header {
+		header[k] = v
+	}
+
+	delete(header, DeadlineHeader)
+	if deadline, ok := ctx.Deadline(); ok {
+		header[DeadlineHeader] = deadline.UTC().Format(time.RFC3339Nano)
+	}
+
+	e.ctx = ctx
+	e.Header = header
+	return e
+}
+
+// Deadline returns the deadline of envelope from it's context, else from
+// it's header if set.
+func (e Envelope) Deadline() (time.Time, bool) {
+	if _, derived := e.ctx.(derivedContext); e.ctx != nil && !derived {
+		if deadline, ok := e.ctx.Deadline(); ok {
+			return deadline, true
+		}
+	}
+
+	if value, ok := e.Header[DeadlineHeader]; ok {
+		if deadline, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return deadline, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Expired returns true if envelope's context was cancelled or it's deadline
+// is before or at provided time.
+func (e Envelope) Expired(now time.Time) bool {
+	if _, derived := e.ctx.(derivedContext); e.ctx != nil && !derived && e.ctx.Err() != nil {
+		return true
+	}
+
+	if deadline, ok := e.Deadline(); ok {
+		return !now.Before(deadline)
+	}
+	return false
 }
 
 // CreateEnvelope returns a new instance of an envelope with provided arguments.
@@ -135,6 +273,7 @@ type Actor interface {
 	DeathWatch
 	Stats
 
+	ClockOwner
 	MailboxOwner
 }
 
@@ -294,6 +433,17 @@ type Descendants interface {
 	GetChild(id string, subID ...string) (Addr, error)
 }
 
+//***********************************
+//  Suspendable
+//***********************************
+
+// Suspendable defines an interface for actors which can be suspended, where
+// messages received while it is not running are stashed, to be replayed
+// once it is started again.
+type Suspendable interface {
+	Suspend(limit int) error
+}
+
 //***********************************
 //  Waiter
 //***********************************
@@ -325,6 +475,42 @@ type MailboxOwner interface {
 	Mailbox() Mailbox
 }
 
+//***********************************
+//  MailboxFactory
+//***********************************
+
+// MailboxFactory defines an interface which exposes a single method to
+// create a new Mailbox for an actor, using provided MailInvoker for
+// reporting mailbox usage.
+type MailboxFactory interface {
+	NewMailbox(MailInvoker) Mailbox
+}
+
+//***********************************
+//  Clock
+//***********************************
+
+// Timer defines an interface which exposes a single method to stop a
+// scheduled function call from running. It returns false if the
+// timer had already fired or been stopped.
+type Timer interface {
+	Stop() bool
+}
+
+// Clock defines an interface which provides the current time and the
+// scheduling of functions to be called after a giving duration. It allows
+// actors, futures and supervisors to be driven by a non-system time source,
+// usually for testing purposes.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(time.Duration, func()) Timer
+}
+
+// ClockOwner exposes a single method to retrieve an implementer's Clock.
+type ClockOwner interface {
+	Clock() Clock
+}
+
 //***********************************
 //  Ancestor
 //***********************************
@@ -470,6 +656,7 @@ type Watchable interface {
 // a giving Addr if possible.
 type DeathWatch interface {
 	DeathWatch(addr Addr) error
+	Unwatch(addr Addr) error
 }
 
 //***********************************
@@ -537,6 +724,18 @@ type Prop struct {
 
 	// MailInvoker defines the invoker called for updating metrics on mailbox usage.
 	MailInvoker MailInvoker
+
+	// Mailboxes provides the factory used to create a Mailbox for the actor if
+	// no Mailbox is provided. Children actors will inherit parent's Prop.Mailboxes
+	// if they are provided none.
+	Mailboxes MailboxFactory
+
+	// Clock provides the time source used by the actor, it's futures and supervisors
+	// for timestamps and delayed operations. Children actors will inherit parent's
+	// Prop.Clock if they are provided none.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
 }
 
 // Spawner exposes a single method to spawn an underline actor returning
@@ -943,6 +1142,13 @@ type MessageInvoker interface {
 	InvokedProcessing(Addr, Envelope)
 }
 
+// MessageInjector defines an interface which a MessageInvoker can implement
+// to modify envelopes sent to an actor before they are delivered into it's
+// mailbox, e.g to set headers derived from the sender.
+type MessageInjector interface {
+	InjectRequest(Addr, Envelope) Envelope
+}
+
 // StateInvoker defines an interface which signals an invocation of state
 // of it's implementer.
 type StateInvoker interface {
@@ -1097,6 +1303,28 @@ type ActorFailureSignal struct {
 // SystemMessage identifies giving type as a system message.
 func (ActorFailureSignal) SystemMessage() {}
 
+// Terminated is delivered into the mailbox of all actors watching a giving
+// actor through DeathWatch, when said actor is stopped, killed or destroyed.
+// It is not delivered when an actor is stopped as part of a restart.
+type Terminated struct {
+	// Addr of the terminated actor, as provided to DeathWatch.
+	Addr Addr
+
+	// Signal is the final state of the terminated actor, which is
+	// either STOPPED, KILLED or DESTROYED.
+	Signal Signal
+
+	// Cause is the last value escalated by the terminated actor for itself
+	// if any, usually the error or panic which led to it's termination.
+	Cause interface{}
+
+	// Stat is the final stat of the terminated actor.
+	Stat Stat
+}
+
+// SystemMessage identifies giving type as a system message.
+func (Terminated) SystemMessage() {}
+
 // PanicEvent is sent when a actor internal routine panics due to message processor
 // or some other error.
 type PanicEvent struct {
@@ -0,0 +1,129 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gokit/actorkit"
+)
+
+var (
+	_ actorkit.Logs = &StdLogs{}
+	_ actorkit.Logs = &KeyValueLogs{}
+)
+
+//*****************************************************************************
+// StdLogs
+//*****************************************************************************
+
+// StdLogs implements the actorkit.Logs interface, writing logs into a *log.Logger
+// from the standard library in the format:
+//
+//	LEVEL message key=value key2="value with spaces"
+//
+// Fields are extracted from known LogMessage types as described by Fields.
+type StdLogs struct {
+	Logger *log.Logger
+}
+
+// NewStdLogs returns a new StdLogs writing into provided logger, using the standard
+// logger of the log package if nil.
+func NewStdLogs(logger *log.Logger) *StdLogs {
+	return &StdLogs{Logger: logger}
+}
+
+// Emit implements the actorkit.Logs interface.
+func (s *StdLogs) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	message, fields := Fields(msg)
+
+	var buf bytes.Buffer
+	buf.WriteString(level.String())
+	buf.WriteString(" ")
+	buf.WriteString(message)
+
+	for _, key := range sortedKeys(fields) {
+		buf.WriteString(" ")
+		buf.WriteString(key)
+		buf.WriteString("=")
+		buf.WriteString(formatValue(fields[key]))
+	}
+
+	if s.Logger == nil {
+		log.Print(buf.String())
+		return
+	}
+	s.Logger.Print(buf.String())
+}
+
+// formatValue returns the text of provided value, quoted if it is empty or holds
+// spaces, quotes or equal signs.
+func formatValue(value interface{}) string {
+	var text string
+	switch item := value.(type) {
+	case string:
+		text = item
+	case []byte:
+		text = string(item)
+	case error:
+		text = item.Error()
+	case fmt.Stringer:
+		text = item.String()
+	case map[string]interface{}, []interface{}:
+		text = string(jsonValue(item))
+	default:
+		text = fmt.Sprintf("%+v", item)
+	}
+
+	if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+//*****************************************************************************
+// KeyValueLogs
+//*****************************************************************************
+
+// KeyValueLogger defines a generic structured logger which logs a alternating
+// sequence of keys and values, as done by many structured logging libraries.
+type KeyValueLogger interface {
+	Log(keyvals ...interface{}) error
+}
+
+// KeyValueFunc implements the KeyValueLogger interface using a function.
+type KeyValueFunc func(keyvals ...interface{}) error
+
+// Log implements the KeyValueLogger interface.
+func (fn KeyValueFunc) Log(keyvals ...interface{}) error {
+	return fn(keyvals...)
+}
+
+// KeyValueLogs implements the actorkit.Logs interface, delivering logs to a
+// KeyValueLogger with the level and message as the first pairs, followed by the
+// fields of the log sorted by name.
+//
+// Fields are extracted from known LogMessage types as described by Fields.
+type KeyValueLogs struct {
+	Logger KeyValueLogger
+}
+
+// NewKeyValueLogs returns a new KeyValueLogs delivering logs to provided logger.
+func NewKeyValueLogs(logger KeyValueLogger) *KeyValueLogs {
+	return &KeyValueLogs{Logger: logger}
+}
+
+// Emit implements the actorkit.Logs interface.
+func (k *KeyValueLogs) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	message, fields := Fields(msg)
+
+	keyvals := make([]interface{}, 0, 4+len(fields)*2)
+	keyvals = append(keyvals, LevelKey, level.String(), MessageKey, message)
+	for _, key := range sortedKeys(fields) {
+		keyvals = append(keyvals, key, fields[key])
+	}
+
+	k.Logger.Log(keyvals...)
+}
@@ -0,0 +1,53 @@
+package logs_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/logs"
+	"github.com/gokit/actorkit/pubsubs"
+)
+
+func TestFields(t *testing.T) {
+	message, fields := logs.Fields(pubsubs.PublishError{Topic: "users", Err: errors.New("broken pipe"), Data: "payload"})
+	require.Equal(t, "broken pipe", message)
+	require.Equal(t, map[string]interface{}{"topic": "users", "data": "payload"}, fields)
+
+	message, fields = logs.Fields(pubsubs.SubscriptionError{Topic: "orders", Err: errors.New("denied")})
+	require.Equal(t, "denied", message)
+	require.Equal(t, map[string]interface{}{"topic": "orders"}, fields)
+
+	message, fields = logs.Fields(actorkit.LogMsgWithContext("connecting", "context", nil).String("url", "nats://localhost"))
+	require.Equal(t, "connecting", message)
+	require.Equal(t, map[string]interface{}{"context": map[string]interface{}{"url": "nats://localhost"}}, fields)
+
+	message, fields = logs.Fields(actorkit.Message("plain"))
+	require.Equal(t, "plain", message)
+	require.Empty(t, fields)
+}
+
+func TestStdLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logs.NewStdLogs(log.New(&buf, "", 0))
+
+	logger.Emit(actorkit.INFO, actorkit.LogMsg("started").String("name", "my actor").Int("count", 3))
+	logger.Emit(actorkit.ERROR, pubsubs.PublishError{Topic: "users", Err: errors.New("broken pipe"), Data: []byte("payload")})
+
+	require.Equal(t, "INFO started count=3 name=\"my actor\"\nERROR broken pipe data=payload topic=users\n", buf.String())
+}
+
+func TestKeyValueLogs(t *testing.T) {
+	var logged []interface{}
+	logger := logs.NewKeyValueLogs(logs.KeyValueFunc(func(keyvals ...interface{}) error {
+		logged = keyvals
+		return nil
+	}))
+
+	logger.Emit(actorkit.WARN, logs.WithFields(actorkit.OpMessage{Detail: "retrying", Data: 2}, map[string]interface{}{"addr": "kit@localhost/1"}))
+	require.Equal(t, []interface{}{"level", "WARN", "message", "retrying", "addr", "kit@localhost/1", "data", 2}, logged)
+}
@@ -0,0 +1,99 @@
+package logs
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/gokit/actorkit"
+)
+
+var _ actorkit.Logs = &AsyncWriter{}
+
+type asyncEntry struct {
+	level actorkit.Level
+	msg   actorkit.LogMessage
+}
+
+// AsyncWriter implements the actorkit.Logs interface, delivering logs to the next
+// Logs from a background goroutine through a bounded buffer, which ensures logging
+// never blocks an actor. Logs emitted while the buffer is full are dropped and
+// counted.
+//
+// AsyncWriter must be closed to deliver all buffered logs and stop it's goroutine.
+type AsyncWriter struct {
+	next    actorkit.Logs
+	entries chan asyncEntry
+	dropped int64
+	waiter  sync.WaitGroup
+
+	cl     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncWriter returns a new AsyncWriter delivering logs to provided Logs, using
+// a buffer of provided size which defaults to 1024 if size is not above zero.
+func NewAsyncWriter(next actorkit.Logs, size int) *AsyncWriter {
+	if size <= 0 {
+		size = 1024
+	}
+
+	w := &AsyncWriter{
+		next:    next,
+		entries: make(chan asyncEntry, size),
+	}
+
+	w.waiter.Add(1)
+	go w.run()
+	return w
+}
+
+// Dropped returns the total logs dropped due to a full buffer or after close.
+func (w *AsyncWriter) Dropped() int64 {
+	return atomic.LoadInt64(&w.dropped)
+}
+
+// Emit implements the actorkit.Logs interface.
+func (w *AsyncWriter) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	// *LogEvent is returned to it's pool once read, hence must be read before it
+	// is handed to another goroutine.
+	if event, ok := msg.(*actorkit.LogEvent); ok {
+		msg = actorkit.Message(event.Message())
+	}
+
+	w.cl.RLock()
+	defer w.cl.RUnlock()
+
+	if w.closed {
+		atomic.AddInt64(&w.dropped, 1)
+		return
+	}
+
+	select {
+	case w.entries <- asyncEntry{level: level, msg: msg}:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+}
+
+// Close stops the AsyncWriter, blocking till all buffered logs are delivered.
+func (w *AsyncWriter) Close() error {
+	w.cl.Lock()
+	if w.closed {
+		w.cl.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	close(w.entries)
+	w.cl.Unlock()
+
+	w.waiter.Wait()
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer w.waiter.Done()
+	for entry := range w.entries {
+		w.next.Emit(entry.level, entry.msg)
+	}
+}
@@ -0,0 +1,66 @@
+package logs
+
+import (
+	"github.com/gokit/actorkit"
+)
+
+// keys of fields added by ContextLogs for all logs of an actor.
+const (
+	AddrKey      = "addr"
+	IDKey        = "id"
+	NamespaceKey = "namespace"
+	ProtocolKey  = "protocol"
+)
+
+var (
+	_ actorkit.ContextLogs = &ContextLogs{}
+	_ actorkit.Logs        = &contextLogger{}
+	_ actorkit.LogMessage  = ContextMessage{}
+)
+
+// ContextMessage implements the actorkit.LogMessage interface, attaching a set of
+// fields to a LogMessage.
+type ContextMessage struct {
+	actorkit.LogMessage
+	Fields map[string]interface{}
+}
+
+// WithFields returns a ContextMessage attaching provided fields to provided message.
+func WithFields(msg actorkit.LogMessage, fields map[string]interface{}) ContextMessage {
+	return ContextMessage{LogMessage: msg, Fields: fields}
+}
+
+// ContextLogs implements the actorkit.ContextLogs interface, returning a Logs for
+// an actor which attaches the address, id, namespace and protocol of the actor as
+// fields of all it's logs, before delivering them to the Next Logs.
+//
+// ContextLogs is inherited by all children of an actor through Prop.ContextLogs.
+type ContextLogs struct {
+	Next actorkit.Logs
+}
+
+// NewContextLogs returns a new ContextLogs delivering logs to provided Logs.
+func NewContextLogs(next actorkit.Logs) *ContextLogs {
+	return &ContextLogs{Next: next}
+}
+
+// Get implements the actorkit.ContextLogs interface.
+func (c *ContextLogs) Get(actor actorkit.Actor) actorkit.Logs {
+	return &contextLogger{actor: actor, next: c.Next}
+}
+
+// contextLogger resolves the fields of it's actor on every log, as an actor's
+// address is only complete once it is attached to it's parent.
+type contextLogger struct {
+	actor actorkit.Actor
+	next  actorkit.Logs
+}
+
+func (c *contextLogger) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	c.next.Emit(level, WithFields(msg, map[string]interface{}{
+		AddrKey:      c.actor.Addr(),
+		IDKey:        c.actor.ID(),
+		NamespaceKey: c.actor.Namespace(),
+		ProtocolKey:  c.actor.Protocol(),
+	}))
+}
@@ -0,0 +1,129 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gokit/actorkit"
+)
+
+// keys of fields written for all records by the JSONWriter.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "message"
+)
+
+var _ actorkit.Logs = &JSONWriter{}
+
+// JSONWriter implements the actorkit.Logs interface, writing each log as a single
+// line of strict JSON into an io.Writer, with the time, level and message fields
+// followed by all fields of the log sorted by name.
+//
+// Fields whose names collide with the time, level or message fields are prefixed
+// with "fields.".
+type JSONWriter struct {
+	// Now sets the function used to retrieve the time of a log, defaulting to
+	// time.Now.
+	Now func() time.Time
+
+	ml  sync.Mutex
+	w   io.Writer
+	buf bytes.Buffer
+	err error
+}
+
+// NewJSONWriter returns a new JSONWriter writing into provided writer.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w, Now: time.Now}
+}
+
+// Err returns the last error returned by the underline writer.
+func (j *JSONWriter) Err() error {
+	j.ml.Lock()
+	defer j.ml.Unlock()
+	return j.err
+}
+
+// Emit implements the actorkit.Logs interface.
+func (j *JSONWriter) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	message, fields := Fields(msg)
+
+	now := time.Now
+	if j.Now != nil {
+		now = j.Now
+	}
+
+	j.ml.Lock()
+	defer j.ml.Unlock()
+
+	j.buf.Reset()
+	j.buf.WriteString("{")
+	writeField(&j.buf, TimeKey, now().UTC().Format(time.RFC3339Nano))
+	j.buf.WriteString(",")
+	writeField(&j.buf, LevelKey, level.String())
+	j.buf.WriteString(",")
+	writeField(&j.buf, MessageKey, message)
+
+	for _, key := range sortedKeys(fields) {
+		name := key
+		if name == TimeKey || name == LevelKey || name == MessageKey {
+			name = "fields." + name
+		}
+
+		j.buf.WriteString(",")
+		writeField(&j.buf, name, fields[key])
+	}
+
+	j.buf.WriteString("}\n")
+
+	if _, err := j.w.Write(j.buf.Bytes()); err != nil {
+		j.err = err
+	}
+}
+
+func writeField(buf *bytes.Buffer, name string, value interface{}) {
+	key, _ := json.Marshal(name)
+	buf.Write(key)
+	buf.WriteString(":")
+	buf.Write(jsonValue(value))
+}
+
+// jsonValue returns the JSON encoding of provided value, where errors are encoded
+// as their messages and values which fail to encode are encoded as formatted strings.
+func jsonValue(value interface{}) []byte {
+	switch item := value.(type) {
+	case json.RawMessage:
+		if json.Valid(item) {
+			return item
+		}
+		value = string(item)
+	case json.Number:
+		return []byte(item)
+	case error:
+		value = item.Error()
+	case fmt.Stringer:
+		value = item.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%+v", value))
+	}
+	return data
+}
+
+// sortedKeys returns the keys of provided fields in sorted order.
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
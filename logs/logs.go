@@ -0,0 +1,169 @@
+// Package logs provides production ready implementations of the actorkit.Logs and
+// actorkit.ContextLogs interfaces, which can be composed together, for example:
+//
+//	writer := logs.NewAsyncWriter(logs.NewJSONWriter(os.Stderr), 1024)
+//	logger := logs.FilterLevel(actorkit.INFO, writer)
+//
+//	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{
+//		ContextLogs: logs.NewContextLogs(logs.NewSampler(logger, 10, 100, time.Second)),
+//	})
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/pubsubs"
+)
+
+// keys of fields extracted from known LogMessage types.
+const (
+	DataKey  = "data"
+	TopicKey = "topic"
+)
+
+// Rank returns the severity rank of provided level, where DEBUG has the lowest
+// and PANIC the highest rank. Unknown levels have a rank of -1.
+//
+// Rank is required as actorkit levels are bit flags which do not follow their
+// severity order.
+func Rank(level actorkit.Level) int {
+	switch level {
+	case actorkit.DEBUG:
+		return 0
+	case actorkit.INFO:
+		return 1
+	case actorkit.WARN:
+		return 2
+	case actorkit.ERROR:
+		return 3
+	case actorkit.PANIC:
+		return 4
+	}
+	return -1
+}
+
+//*****************************************************************************
+// LevelFilter
+//*****************************************************************************
+
+var _ actorkit.Logs = &LevelFilter{}
+
+// LevelFilter implements the actorkit.Logs interface, delivering only logs with
+// a severity at or above it's minimum level to it's next Logs.
+type LevelFilter struct {
+	Min  actorkit.Level
+	Next actorkit.Logs
+}
+
+// FilterLevel returns a new LevelFilter which delivers logs at or above provided
+// minimum level to provided Logs.
+func FilterLevel(min actorkit.Level, next actorkit.Logs) *LevelFilter {
+	return &LevelFilter{Min: min, Next: next}
+}
+
+// Emit implements the actorkit.Logs interface.
+func (l *LevelFilter) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	if Rank(level) < Rank(l.Min) {
+		return
+	}
+	l.Next.Emit(level, msg)
+}
+
+//*****************************************************************************
+// Fields
+//*****************************************************************************
+
+// Fields returns the message and fields of provided LogMessage, extracting the
+// fields of the following messages instead of flattening them into a string:
+//
+//  1. *actorkit.LogEvent, whose JSON object fields are extracted with it's "message"
+//     field used as the message.
+//  2. actorkit.OpMessage, whose Data is extracted as the "data" field.
+//  3. ContextMessage, whose Fields are extracted along the fields of it's message.
+//  4. pubsubs error types, whose Topic and Data are extracted as the "topic" and
+//     "data" fields.
+func Fields(msg actorkit.LogMessage) (string, map[string]interface{}) {
+	fields := map[string]interface{}{}
+	message := collectFields(msg, fields)
+	return message, fields
+}
+
+func collectFields(msg actorkit.LogMessage, fields map[string]interface{}) string {
+	switch item := msg.(type) {
+	case ContextMessage:
+		message := collectFields(item.LogMessage, fields)
+		for key, value := range item.Fields {
+			if _, ok := fields[key]; !ok {
+				fields[key] = value
+			}
+		}
+		return message
+	case *ContextMessage:
+		return collectFields(*item, fields)
+	case actorkit.OpMessage:
+		addField(fields, DataKey, item.Data)
+		return item.Detail
+	case pubsubs.PublishError:
+		return topicFields(fields, item, item.Topic, item.Data)
+	case pubsubs.MarshalingError:
+		return topicFields(fields, item, item.Topic, item.Data)
+	case pubsubs.UnmarshalingError:
+		return topicFields(fields, item, item.Topic, item.Data)
+	case pubsubs.MessageHandlingError:
+		return topicFields(fields, item, item.Topic, item.Data)
+	case pubsubs.OpError:
+		return topicFields(fields, item, item.Topic, nil)
+	case pubsubs.SubscriptionError:
+		return topicFields(fields, item, item.Topic, nil)
+	case pubsubs.DesubscriptionError:
+		return topicFields(fields, item, item.Topic, nil)
+	}
+
+	return parseMessage(msg.Message(), fields)
+}
+
+// parseMessage extracts the fields of provided message if it is a JSON object, as
+// produced by *actorkit.LogEvent, returning it's "message" field as the message.
+func parseMessage(message string, fields map[string]interface{}) string {
+	if len(message) == 0 || message[0] != '{' {
+		return message
+	}
+
+	var object map[string]interface{}
+
+	decoder := json.NewDecoder(strings.NewReader(message))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return message
+	}
+
+	var text string
+	if value, ok := object[MessageKey]; ok {
+		text = fmt.Sprint(value)
+		delete(object, MessageKey)
+	}
+
+	for key, value := range object {
+		fields[key] = value
+	}
+	return text
+}
+
+func topicFields(fields map[string]interface{}, msg actorkit.LogMessage, topic string, data interface{}) string {
+	addField(fields, TopicKey, topic)
+	addField(fields, DataKey, data)
+	return msg.Message()
+}
+
+func addField(fields map[string]interface{}, key string, value interface{}) {
+	if value == nil {
+		return
+	}
+	if text, ok := value.(string); ok && text == "" {
+		return
+	}
+	fields[key] = value
+}
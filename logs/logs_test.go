@@ -0,0 +1,193 @@
+package logs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/logs"
+)
+
+type entry struct {
+	Level actorkit.Level
+	Msg   actorkit.LogMessage
+}
+
+type collector struct {
+	ml      sync.Mutex
+	entries []entry
+}
+
+func (c *collector) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	c.ml.Lock()
+	c.entries = append(c.entries, entry{Level: level, Msg: msg})
+	c.ml.Unlock()
+}
+
+func (c *collector) Len() int {
+	c.ml.Lock()
+	defer c.ml.Unlock()
+	return len(c.entries)
+}
+
+func decodeLines(t *testing.T, content string) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := logs.NewJSONWriter(&buf)
+	writer.Now = func() time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	writer.Emit(actorkit.INFO, actorkit.Message("hello \"world\""))
+	writer.Emit(actorkit.ERROR, actorkit.OpMessage{Detail: "failed", Data: errors.New("boom")})
+	actorkit.LogMsg("event").String("name", "wally").Int("count", 2).WriteDebug(writer)
+	writer.Emit(actorkit.WARN, logs.WithFields(actorkit.Message("ctx"), map[string]interface{}{"level": 1, "id": "x"}))
+
+	require.Equal(t, `{"time":"2018-01-01T00:00:00Z","level":"INFO","message":"hello \"world\""}`, strings.SplitN(buf.String(), "\n", 2)[0])
+
+	records := decodeLines(t, buf.String())
+	require.Len(t, records, 4)
+
+	require.Equal(t, "ERROR", records[1]["level"])
+	require.Equal(t, "failed", records[1]["message"])
+	require.Equal(t, "boom", records[1]["data"])
+
+	require.Equal(t, "DEBUG", records[2]["level"])
+	require.Equal(t, "event", records[2]["message"])
+	require.Equal(t, "wally", records[2]["name"])
+	require.Equal(t, float64(2), records[2]["count"])
+
+	require.Equal(t, "WARN", records[3]["level"])
+	require.Equal(t, "x", records[3]["id"])
+	require.Equal(t, float64(1), records[3]["fields.level"])
+}
+
+func TestLevelFilter(t *testing.T) {
+	c := &collector{}
+	filter := logs.FilterLevel(actorkit.WARN, c)
+
+	filter.Emit(actorkit.DEBUG, actorkit.Message("debug"))
+	filter.Emit(actorkit.INFO, actorkit.Message("info"))
+	filter.Emit(actorkit.WARN, actorkit.Message("warn"))
+	filter.Emit(actorkit.ERROR, actorkit.Message("error"))
+	filter.Emit(actorkit.PANIC, actorkit.Message("panic"))
+
+	require.Len(t, c.entries, 3)
+	require.Equal(t, actorkit.WARN, c.entries[0].Level)
+}
+
+func TestContextLogs(t *testing.T) {
+	var buf syncBuffer
+	writer := logs.NewJSONWriter(&buf)
+
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{
+		ContextLogs: logs.NewContextLogs(logs.FilterLevel(actorkit.DEBUG, writer)),
+	})
+	require.NoError(t, err)
+
+	child, err := system.Spawn("child", actorkit.Prop{Behaviour: actorkit.FromBehaviourFunc(func(_ actorkit.Addr, _ actorkit.Envelope) {})})
+	require.NoError(t, err)
+	require.NoError(t, child.Actor().Stop())
+
+	actorkit.Destroy(system)
+
+	var found bool
+	for _, record := range decodeLines(t, buf.String()) {
+		if record["addr"] == child.Actor().Addr() {
+			found = true
+			require.Equal(t, child.ID(), record["id"])
+			require.Equal(t, "localhost", record["namespace"])
+			require.Equal(t, "kit", record["protocol"])
+		}
+	}
+	require.True(t, found)
+}
+
+func TestSampler(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := &collector{}
+	sampler := logs.NewSampler(c, 2, 3, time.Second)
+	sampler.Now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		sampler.Emit(actorkit.DEBUG, logs.WithFields(actorkit.Message("repeat"), map[string]interface{}{"i": i}))
+	}
+
+	// first 2, then every 3rd: 5th and 8th.
+	require.Equal(t, 4, c.Len())
+	require.Equal(t, int64(6), sampler.Sampled())
+
+	sampler.Emit(actorkit.DEBUG, actorkit.Message("other"))
+	sampler.Emit(actorkit.ERROR, actorkit.Message("repeat"))
+	require.Equal(t, 6, c.Len())
+
+	now = now.Add(time.Second)
+	sampler.Emit(actorkit.DEBUG, actorkit.Message("repeat"))
+	require.Equal(t, 7, c.Len())
+}
+
+type blocking struct {
+	collector
+	release chan struct{}
+}
+
+func (b *blocking) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	<-b.release
+	b.collector.Emit(level, msg)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	next := &blocking{release: make(chan struct{})}
+	writer := logs.NewAsyncWriter(next, 2)
+
+	// first log is held by the goroutine, following logs fill the buffer.
+	emitted := 1
+	writer.Emit(actorkit.INFO, actorkit.Message("1"))
+	for ; emitted < 100 && writer.Dropped() == 0; emitted++ {
+		writer.Emit(actorkit.INFO, actorkit.LogMsg("event"))
+	}
+
+	require.Equal(t, int64(1), writer.Dropped())
+	close(next.release)
+
+	require.NoError(t, writer.Close())
+	require.Equal(t, emitted-1, next.Len())
+
+	writer.Emit(actorkit.INFO, actorkit.Message("closed"))
+	require.Equal(t, int64(2), writer.Dropped())
+	require.Equal(t, emitted-1, next.Len())
+}
+
+type syncBuffer struct {
+	ml  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.ml.Lock()
+	defer s.ml.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.ml.Lock()
+	defer s.ml.Unlock()
+	return s.buf.String()
+}
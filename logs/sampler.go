@@ -0,0 +1,106 @@
+package logs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gokit/actorkit"
+)
+
+var _ actorkit.Logs = &Sampler{}
+
+// Sampler implements the actorkit.Logs interface, sampling repetitive DEBUG logs
+// such as those emitted by an actor's life cycle management.
+//
+// Within every Tick, the first Initial DEBUG logs of the same message are delivered
+// to the Next Logs, after which only every Thereafter-th log of that message is
+// delivered. Logs of all other levels are always delivered.
+type Sampler struct {
+	Next       actorkit.Logs
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+
+	// Now sets the function used to retrieve current time, defaulting to time.Now.
+	Now func() time.Time
+
+	sampled int64
+
+	ml     sync.Mutex
+	reset  time.Time
+	counts map[string]int
+}
+
+// NewSampler returns a new Sampler delivering sampled logs to provided Logs.
+func NewSampler(next actorkit.Logs, initial int, thereafter int, tick time.Duration) *Sampler {
+	return &Sampler{
+		Next:       next,
+		Tick:       tick,
+		Initial:    initial,
+		Thereafter: thereafter,
+		Now:        time.Now,
+	}
+}
+
+// Sampled returns the total DEBUG logs dropped by sampler.
+func (s *Sampler) Sampled() int64 {
+	return atomic.LoadInt64(&s.sampled)
+}
+
+// Emit implements the actorkit.Logs interface.
+func (s *Sampler) Emit(level actorkit.Level, msg actorkit.LogMessage) {
+	if level != actorkit.DEBUG || s.allow(sampleKey(msg)) {
+		s.Next.Emit(level, msg)
+		return
+	}
+	atomic.AddInt64(&s.sampled, 1)
+}
+
+func (s *Sampler) allow(key string) bool {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	s.ml.Lock()
+	defer s.ml.Unlock()
+
+	current := now()
+	if s.counts == nil || !current.Before(s.reset) {
+		s.counts = map[string]int{}
+		s.reset = current.Add(s.Tick)
+	}
+
+	s.counts[key]++
+	count := s.counts[key]
+
+	if count <= s.Initial {
+		return true
+	}
+
+	if s.Thereafter <= 0 {
+		return false
+	}
+	return (count-s.Initial)%s.Thereafter == 0
+}
+
+// sampleKey returns the message used to identify repetitions of provided message,
+// which ignores attached context fields.
+func sampleKey(msg actorkit.LogMessage) string {
+	for {
+		switch item := msg.(type) {
+		case ContextMessage:
+			msg = item.LogMessage
+			continue
+		case *ContextMessage:
+			msg = item.LogMessage
+			continue
+		case actorkit.OpMessage:
+			return item.Detail
+		case *actorkit.LogEvent:
+			return "" // *LogEvent can only be read once, hence all are treated as same.
+		}
+		return msg.Message()
+	}
+}
@@ -0,0 +1,362 @@
+// Package metrics implements the actorkit MailInvoker, MessageInvoker, StateInvoker and
+// SupervisionInvoker interfaces, tracking mailbox, message processing, lifecycle and
+// supervision metrics of actors, which are served in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gokit/actorkit"
+)
+
+const (
+	defaultNamespace = "actorkit"
+	contentType      = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets defines the default latency buckets in seconds used for
+// processing latency histograms.
+var DefaultBuckets = []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var (
+	_ actorkit.MailInvoker        = &Metrics{}
+	_ actorkit.StateInvoker       = &Metrics{}
+	_ actorkit.MessageInvoker     = &Metrics{}
+	_ actorkit.SupervisionInvoker = &Metrics{}
+)
+
+// Config defines configuration for a Metrics.
+type Config struct {
+	// Namespace sets the prefix of all metric names.
+	//
+	// Defaults to "actorkit".
+	Namespace string
+
+	// Buckets sets the upper bounds in seconds of processing latency histograms.
+	//
+	// Defaults to DefaultBuckets.
+	Buckets []float64
+
+	// Now sets the function used to retrieve current time for latency
+	// measurements.
+	//
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Metrics implements the actorkit.MailInvoker, actorkit.MessageInvoker, actorkit.StateInvoker
+// and actorkit.SupervisionInvoker interfaces, tracking:
+//
+//  1. Mailbox depth, enqueued, dequeued and dropped messages per actor, where the
+//     depth is read from the actor's mailbox when metrics are written or read.
+//  2. Requested messages and processing latency histograms per actor and message type.
+//  3. Starts, stops, restarts, kills, destructions and panics per actor.
+//  4. Supervision stops, kills, destructions and restarts per actor.
+//
+// A single Metrics can be used as all invokers of a Prop, which are then inherited by
+// all children actors. Supervisors take their invoker directly, hence a Metrics must be
+// provided to them as their SupervisionInvoker.
+//
+// Metrics implements the http.Handler interface, serving all metrics in the Prometheus
+// text exposition format, which can be mounted on any path.
+type Metrics struct {
+	config   Config
+	registry *registry
+
+	pl      sync.Mutex
+	pending map[string]time.Time
+	actors  map[string]actorkit.Addr
+}
+
+// New returns a new instance of Metrics using provided configuration.
+func New(config Config) *Metrics {
+	if config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+
+	if len(config.Buckets) == 0 {
+		config.Buckets = DefaultBuckets
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	m := &Metrics{
+		config:   config,
+		registry: newRegistry(),
+		pending:  map[string]time.Time{},
+		actors:   map[string]actorkit.Addr{},
+	}
+
+	m.register(gaugeType, "mailbox_depth", "Current total of messages within actor mailbox.", "actor")
+	m.register(counterType, "mailbox_enqueued_total", "Total messages added into actor mailbox.", "actor")
+	m.register(counterType, "mailbox_dequeued_total", "Total messages retrieved from actor mailbox.", "actor")
+	m.register(counterType, "mailbox_dropped_total", "Total messages dropped by actor mailbox.", "actor")
+	m.register(counterType, "mailbox_full_total", "Total times a mailbox was found full.")
+	m.register(counterType, "mailbox_empty_total", "Total times a mailbox was found empty.")
+	m.register(counterType, "messages_requested_total", "Total messages delivered to actor.", "actor", "type")
+	m.register(counterType, "messages_processed_total", "Total messages processed by actor.", "actor", "type")
+	m.register(histogramType, "message_processing_seconds", "Latency of message processing by actor in seconds.", "actor", "type")
+	m.register(counterType, "actor_started_total", "Total times actor was started.", "actor")
+	m.register(counterType, "actor_stopped_total", "Total times actor was stopped.", "actor")
+	m.register(counterType, "actor_restarted_total", "Total times actor was restarted.", "actor")
+	m.register(counterType, "actor_killed_total", "Total times actor was killed.", "actor")
+	m.register(counterType, "actor_destroyed_total", "Total times actor was destroyed.", "actor")
+	m.register(counterType, "actor_panics_total", "Total panics recovered from actor.", "actor")
+	m.register(counterType, "supervisor_stops_total", "Total stops of actor by supervisor.", "actor")
+	m.register(counterType, "supervisor_kills_total", "Total kills of actor by supervisor.", "actor")
+	m.register(counterType, "supervisor_destroys_total", "Total destructions of actor by supervisor.", "actor")
+	m.register(counterType, "supervisor_restarts_total", "Total restarts of actor by supervisor.", "actor")
+
+	return m
+}
+
+// Prop returns a copy of provided Prop using Metrics as it's mail, message and
+// state invoker.
+func (m *Metrics) Prop(prop actorkit.Prop) actorkit.Prop {
+	prop.MailInvoker = m
+	prop.StateInvoker = m
+	prop.MessageInvoker = m
+	return prop
+}
+
+// Value returns the current value of a counter or gauge of giving name without
+// namespace, for provided label values. It returns false if the metric is not
+// known or has no value for provided label values.
+func (m *Metrics) Value(name string, labels ...string) (float64, bool) {
+	m.collect()
+	return m.registry.value(m.name(name), labels...)
+}
+
+// Write writes all metrics into provided writer in the Prometheus text
+// exposition format.
+func (m *Metrics) Write(w io.Writer) error {
+	m.collect()
+	return m.registry.write(w)
+}
+
+// Handler returns a http.Handler serving all metrics in the Prometheus text
+// exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return m
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	m.Write(w)
+}
+
+//*****************************************************************************
+// MailInvoker
+//*****************************************************************************
+
+// InvokedFull implements the actorkit.MailInvoker interface.
+func (m *Metrics) InvokedFull() {
+	m.add("mailbox_full_total", 1)
+}
+
+// InvokedEmpty implements the actorkit.MailInvoker interface.
+func (m *Metrics) InvokedEmpty() {
+	m.add("mailbox_empty_total", 1)
+}
+
+// InvokedDropped implements the actorkit.MailInvoker interface.
+func (m *Metrics) InvokedDropped(addr actorkit.Addr, env actorkit.Envelope) {
+	m.add("mailbox_dropped_total", 1, addrOf(addr))
+}
+
+// InvokedReceived implements the actorkit.MailInvoker interface.
+func (m *Metrics) InvokedReceived(addr actorkit.Addr, env actorkit.Envelope) {
+	m.track(addr)
+	m.add("mailbox_enqueued_total", 1, addrOf(addr))
+}
+
+// InvokedDispatched implements the actorkit.MailInvoker interface.
+func (m *Metrics) InvokedDispatched(addr actorkit.Addr, env actorkit.Envelope) {
+	m.add("mailbox_dequeued_total", 1, addrOf(addr))
+}
+
+//*****************************************************************************
+// MessageInvoker
+//*****************************************************************************
+
+// InvokedRequest implements the actorkit.MessageInvoker interface.
+func (m *Metrics) InvokedRequest(addr actorkit.Addr, env actorkit.Envelope) {
+	m.add("messages_requested_total", 1, addrOf(addr), typeOf(env))
+}
+
+// InvokedProcessing implements the actorkit.MessageInvoker interface.
+//
+// As an actor processes a message at a time, the start of processing is kept
+// per actor, which is removed once processed or when the actor panics or is
+// destroyed.
+func (m *Metrics) InvokedProcessing(addr actorkit.Addr, env actorkit.Envelope) {
+	m.pl.Lock()
+	m.pending[addrOf(addr)] = m.config.Now()
+	m.pl.Unlock()
+}
+
+// InvokedProcessed implements the actorkit.MessageInvoker interface.
+func (m *Metrics) InvokedProcessed(addr actorkit.Addr, env actorkit.Envelope) {
+	key := addrOf(addr)
+
+	m.pl.Lock()
+	started, ok := m.pending[key]
+	delete(m.pending, key)
+	m.pl.Unlock()
+
+	m.add("messages_processed_total", 1, addrOf(addr), typeOf(env))
+	if ok {
+		elapsed := m.config.Now().Sub(started).Seconds()
+		m.registry.observe(m.name("message_processing_seconds"), elapsed, addrOf(addr), typeOf(env))
+	}
+}
+
+//*****************************************************************************
+// StateInvoker
+//*****************************************************************************
+
+// InvokedStarted implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedStarted(target interface{}) {
+	if addr, ok := target.(actorkit.Addr); ok {
+		m.track(addr)
+	}
+	m.add("actor_started_total", 1, targetOf(target))
+}
+
+// InvokedStopped implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedStopped(target interface{}) {
+	m.add("actor_stopped_total", 1, targetOf(target))
+}
+
+// InvokedRestarted implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedRestarted(target interface{}) {
+	m.add("actor_restarted_total", 1, targetOf(target))
+}
+
+// InvokedKilled implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedKilled(target interface{}) {
+	m.add("actor_killed_total", 1, targetOf(target))
+}
+
+// InvokedDestroyed implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedDestroyed(target interface{}) {
+	key := targetOf(target)
+
+	m.pl.Lock()
+	delete(m.pending, key)
+	delete(m.actors, key)
+	m.pl.Unlock()
+
+	m.registry.remove(m.name("mailbox_depth"), key)
+	m.add("actor_destroyed_total", 1, key)
+}
+
+// InvokedPanic implements the actorkit.StateInvoker interface.
+func (m *Metrics) InvokedPanic(addr actorkit.Addr, _ actorkit.PanicEvent) {
+	m.pl.Lock()
+	delete(m.pending, addrOf(addr))
+	m.pl.Unlock()
+
+	m.add("actor_panics_total", 1, addrOf(addr))
+}
+
+//*****************************************************************************
+// SupervisionInvoker
+//*****************************************************************************
+
+// InvokedStop implements the actorkit.SupervisionInvoker interface.
+func (m *Metrics) InvokedStop(_ interface{}, _ actorkit.Stat, addr actorkit.Addr, _ actorkit.Actor) {
+	m.add("supervisor_stops_total", 1, addrOf(addr))
+}
+
+// InvokedKill implements the actorkit.SupervisionInvoker interface.
+func (m *Metrics) InvokedKill(_ interface{}, _ actorkit.Stat, addr actorkit.Addr, _ actorkit.Actor) {
+	m.add("supervisor_kills_total", 1, addrOf(addr))
+}
+
+// InvokedDestroy implements the actorkit.SupervisionInvoker interface.
+func (m *Metrics) InvokedDestroy(_ interface{}, _ actorkit.Stat, addr actorkit.Addr, _ actorkit.Actor) {
+	m.add("supervisor_destroys_total", 1, addrOf(addr))
+}
+
+// InvokedRestart implements the actorkit.SupervisionInvoker interface.
+func (m *Metrics) InvokedRestart(_ interface{}, _ actorkit.Stat, addr actorkit.Addr, _ actorkit.Actor) {
+	m.add("supervisor_restarts_total", 1, addrOf(addr))
+}
+
+//*****************************************************************************
+// internal functions
+//*****************************************************************************
+
+func (m *Metrics) name(name string) string {
+	return m.config.Namespace + "_" + name
+}
+
+func (m *Metrics) register(kind string, name string, help string, labels ...string) {
+	m.registry.register(kind, m.name(name), help, m.config.Buckets, labels...)
+}
+
+func (m *Metrics) add(name string, delta float64, labels ...string) {
+	m.registry.add(m.name(name), delta, labels...)
+}
+
+// track adds the actor of giving address into the actors whose mailbox depth
+// is collected.
+func (m *Metrics) track(addr actorkit.Addr) {
+	if addr == nil || addr.Actor() == nil {
+		return
+	}
+
+	key := addrOf(addr)
+
+	m.pl.Lock()
+	if _, ok := m.actors[key]; !ok {
+		m.actors[key] = addr
+	}
+	m.pl.Unlock()
+}
+
+// collect sets the mailbox depth of all tracked actors from their mailboxes.
+func (m *Metrics) collect() {
+	m.pl.Lock()
+	actors := make(map[string]actorkit.Addr, len(m.actors))
+	for key, addr := range m.actors {
+		actors[key] = addr
+	}
+	m.pl.Unlock()
+
+	for key, addr := range actors {
+		m.registry.set(m.name("mailbox_depth"), float64(addr.Actor().Mailbox().Total()), key)
+	}
+}
+
+// addrOf returns the address of the actor of giving address, ensuring all
+// service addresses of an actor share the same label.
+func addrOf(addr actorkit.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if actor := addr.Actor(); actor != nil {
+		return actor.Addr()
+	}
+	return addr.Addr()
+}
+
+func typeOf(env actorkit.Envelope) string {
+	return fmt.Sprintf("%T", env.Data)
+}
+
+func targetOf(target interface{}) string {
+	if addr, ok := target.(actorkit.Addr); ok {
+		return addrOf(addr)
+	}
+	return fmt.Sprintf("%v", target)
+}
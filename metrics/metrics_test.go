@@ -0,0 +1,132 @@
+package metrics_test
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/metrics"
+)
+
+type recorder struct {
+	received chan actorkit.Envelope
+}
+
+func (r *recorder) Action(_ actorkit.Addr, env actorkit.Envelope) {
+	r.received <- env
+}
+
+func value(m *metrics.Metrics, name string, labels ...string) float64 {
+	val, _ := m.Value(name, labels...)
+	return val
+}
+
+func TestMetricsTracksActors(t *testing.T) {
+	m := metrics.New(metrics.Config{})
+
+	system, err := actorkit.Ancestor("kit", "localhost", m.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	rec := &recorder{received: make(chan actorkit.Envelope, 2)}
+	target, err := system.Spawn("target", actorkit.Prop{Behaviour: rec})
+	require.NoError(t, err)
+
+	require.NoError(t, target.Send("hello", nil))
+	require.NoError(t, target.Send("world", nil))
+
+	<-rec.received
+	<-rec.received
+
+	for i := 0; i < 100; i++ {
+		if value(m, "messages_processed_total", target.Actor().Addr(), "string") == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Equal(t, float64(2), value(m, "messages_processed_total", target.Actor().Addr(), "string"))
+	require.Equal(t, float64(2), value(m, "messages_requested_total", target.Actor().Addr(), "string"))
+	require.Equal(t, float64(2), value(m, "mailbox_enqueued_total", target.Actor().Addr()))
+	require.Equal(t, float64(2), value(m, "mailbox_dequeued_total", target.Actor().Addr()))
+	require.Equal(t, float64(0), value(m, "mailbox_depth", target.Actor().Addr()))
+	require.Equal(t, float64(1), value(m, "actor_started_total", target.Actor().Addr()))
+
+	require.NoError(t, target.Actor().Stop())
+	require.Equal(t, float64(1), value(m, "actor_stopped_total", target.Actor().Addr()))
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	content := string(body)
+	require.True(t, strings.Contains(content, "# TYPE actorkit_message_processing_seconds histogram"))
+	require.True(t, strings.Contains(content, `actorkit_messages_processed_total{actor="`+target.Actor().Addr()+`",type="string"} 2`))
+	require.True(t, strings.Contains(content, `actorkit_message_processing_seconds_bucket{actor="`+target.Actor().Addr()+`",type="string",le="+Inf"} 2`))
+	require.True(t, strings.Contains(content, `actorkit_message_processing_seconds_count{actor="`+target.Actor().Addr()+`",type="string"} 2`))
+}
+
+func TestMetricsNamespace(t *testing.T) {
+	m := metrics.New(metrics.Config{Namespace: "app"})
+	m.InvokedFull()
+	m.InvokedFull()
+
+	var b strings.Builder
+	require.NoError(t, m.Write(&b))
+	require.True(t, strings.Contains(b.String(), "app_mailbox_full_total 2\n"))
+	require.Equal(t, float64(2), value(m, "mailbox_full_total"))
+}
+
+func TestMetricsUnknownValues(t *testing.T) {
+	m := metrics.New(metrics.Config{})
+
+	_, ok := m.Value("mailbox_fulll_total")
+	require.False(t, ok)
+
+	_, ok = m.Value("actor_started_total", "unknown")
+	require.False(t, ok)
+
+	var b strings.Builder
+	require.NoError(t, m.Write(&b))
+	require.False(t, strings.Contains(b.String(), "unknown"))
+}
+
+func TestMetricsMailboxDepth(t *testing.T) {
+	m := metrics.New(metrics.Config{})
+
+	system, err := actorkit.Ancestor("kit", "localhost", m.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	release := make(chan struct{})
+	target, err := system.Spawn("target", actorkit.Prop{
+		Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+			<-release
+		}),
+	})
+	require.NoError(t, err)
+	defer close(release)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, target.Send(i, nil))
+	}
+
+	for i := 0; i < 100 && target.Actor().Mailbox().Total() != 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	depth, ok := m.Value("mailbox_depth", target.Actor().Addr())
+	require.True(t, ok)
+	require.Equal(t, float64(2), depth)
+
+	target.Actor().Mailbox().Clear()
+	require.Equal(t, float64(0), value(m, "mailbox_depth", target.Actor().Addr()))
+}
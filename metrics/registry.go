@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metric types of the Prometheus text exposition format.
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// series holds the value of a single metric for a set of labels.
+type series struct {
+	labels []string
+	value  float64
+
+	// histogram values.
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// family holds all series of a single metric name.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// registry implements a minimal store of counters, gauges and histograms which
+// can be written in the Prometheus text exposition format.
+type registry struct {
+	ml       sync.Mutex
+	families map[string]*family
+}
+
+func newRegistry() *registry {
+	return &registry{families: map[string]*family{}}
+}
+
+func (r *registry) register(kind string, name string, help string, buckets []float64, labels ...string) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	r.families[name] = &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+}
+
+// get returns the series of giving family for provided label values, creating it
+// if not found. It returns false if no family of giving name is registered. It
+// expects lock to be held.
+func (r *registry) get(name string, values ...string) (*series, bool) {
+	fam, ok := r.families[name]
+	if !ok {
+		return nil, false
+	}
+
+	key := strings.Join(values, "\xff")
+	if item, ok := fam.series[key]; ok {
+		return item, true
+	}
+
+	item := &series{labels: values}
+	if fam.kind == histogramType {
+		item.buckets = make([]uint64, len(fam.buckets))
+	}
+
+	fam.series[key] = item
+	return item, true
+}
+
+// add adds provided delta to the counter or gauge of giving name.
+func (r *registry) add(name string, delta float64, values ...string) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	if item, ok := r.get(name, values...); ok {
+		item.value += delta
+	}
+}
+
+// set sets provided value as the value of the gauge of giving name.
+func (r *registry) set(name string, value float64, values ...string) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	if item, ok := r.get(name, values...); ok {
+		item.value = value
+	}
+}
+
+// remove removes the series of giving family for provided label values.
+func (r *registry) remove(name string, values ...string) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	if fam, ok := r.families[name]; ok {
+		delete(fam.series, strings.Join(values, "\xff"))
+	}
+}
+
+// observe records provided value into the histogram of giving name.
+func (r *registry) observe(name string, value float64, values ...string) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	item, ok := r.get(name, values...)
+	if !ok {
+		return
+	}
+
+	for index, bound := range r.families[name].buckets {
+		if value <= bound {
+			item.buckets[index]++
+		}
+	}
+
+	item.count++
+	item.sum += value
+}
+
+// value returns the current value of the counter or gauge of giving name
+// without creating it's series, returning false if no such series exists.
+func (r *registry) value(name string, values ...string) (float64, bool) {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	fam, ok := r.families[name]
+	if !ok {
+		return 0, false
+	}
+
+	item, ok := fam.series[strings.Join(values, "\xff")]
+	if !ok {
+		return 0, false
+	}
+	return item.value, true
+}
+
+// write writes all metrics in the Prometheus text exposition format, sorted
+// by name and labels.
+func (r *registry) write(w io.Writer) error {
+	r.ml.Lock()
+	defer r.ml.Unlock()
+
+	buf := bufio.NewWriter(w)
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fam := r.families[name]
+		fmt.Fprintf(buf, "# HELP %s %s\n", fam.name, fam.help)
+		fmt.Fprintf(buf, "# TYPE %s %s\n", fam.name, fam.kind)
+
+		keys := make([]string, 0, len(fam.series))
+		for key := range fam.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			item := fam.series[key]
+			if fam.kind != histogramType {
+				fmt.Fprintf(buf, "%s%s %s\n", fam.name, formatLabels(fam.labels, item.labels), formatFloat(item.value))
+				continue
+			}
+
+			names := append(append([]string(nil), fam.labels...), "le")
+			values := append(append([]string(nil), item.labels...), "")
+
+			for index, bound := range fam.buckets {
+				values[len(values)-1] = formatFloat(bound)
+				fmt.Fprintf(buf, "%s_bucket%s %d\n", fam.name, formatLabels(names, values), item.buckets[index])
+			}
+
+			values[len(values)-1] = "+Inf"
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", fam.name, formatLabels(names, values), item.count)
+			fmt.Fprintf(buf, "%s_sum%s %s\n", fam.name, formatLabels(fam.labels, item.labels), formatFloat(item.sum))
+			fmt.Fprintf(buf, "%s_count%s %d\n", fam.name, formatLabels(fam.labels, item.labels), item.count)
+		}
+	}
+
+	return buf.Flush()
+}
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("{")
+	for index, name := range names {
+		if index > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(name)
+		b.WriteString("=\"")
+		b.WriteString(escapeLabel(values[index]))
+		b.WriteString("\"")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
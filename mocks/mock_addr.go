@@ -17,6 +17,10 @@ func (am *AddrImpl) DeathWatch(addr actorkit.Addr) error {
 	return errors.New("not supported")
 }
 
+func (am *AddrImpl) Unwatch(addr actorkit.Addr) error {
+	return errors.New("not supported")
+}
+
 func (am *AddrImpl) AddDiscovery(service actorkit.DiscoveryService) error {
 	return errors.New("not supported")
 }
@@ -0,0 +1,316 @@
+package platform
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/errors"
+)
+
+// default phases of a Shutdown.
+const (
+	PhaseStopTraffic        = "stop-traffic"
+	PhaseDrainSubscriptions = "drain-subscriptions"
+	PhaseStopActors         = "stop-actors"
+	PhaseFlushLogs          = "flush-logs"
+	PhaseCloseBrokers       = "close-brokers"
+)
+
+const defaultPhaseTimeout = 10 * time.Second
+
+var (
+	// ErrUnknownPhase is returned when a task is added to a phase which does not exist.
+	ErrUnknownPhase = errors.New("shutdown phase does not exist")
+
+	// ErrShutdownStarted is returned when a task is added after a shutdown has started.
+	ErrShutdownStarted = errors.New("shutdown already started")
+)
+
+//*****************************************************************************
+// Phase
+//*****************************************************************************
+
+// Phase defines a named stage of a Shutdown, where all tasks of a phase are run
+// concurrently and must finish within it's timeout. Phases are run in order.
+type Phase struct {
+	Name string
+
+	// Timeout sets the maximum duration all tasks of phase have to finish
+	// before they are reported as overran.
+	//
+	// Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// DefaultPhases returns the default phases of a Shutdown, in order: stop
+// accepting traffic, drain pubsub subscriptions, stop actors, flush logs and
+// close brokers.
+func DefaultPhases() []Phase {
+	return []Phase{
+		{Name: PhaseStopTraffic, Timeout: defaultPhaseTimeout},
+		{Name: PhaseDrainSubscriptions, Timeout: defaultPhaseTimeout},
+		{Name: PhaseStopActors, Timeout: defaultPhaseTimeout},
+		{Name: PhaseFlushLogs, Timeout: defaultPhaseTimeout},
+		{Name: PhaseCloseBrokers, Timeout: defaultPhaseTimeout},
+	}
+}
+
+// TaskFunc defines a function to be run during a phase of a Shutdown, it is
+// expected to return once the provided context is done.
+type TaskFunc func(context.Context) error
+
+//*****************************************************************************
+// Report
+//*****************************************************************************
+
+// TaskReport details the run of a single task of a Shutdown.
+type TaskReport struct {
+	Phase    string
+	Task     string
+	Duration time.Duration
+	Err      error
+
+	// Overran is true if task did not finish within it's phase timeout.
+	Overran bool
+}
+
+// Report details the run of a Shutdown.
+type Report struct {
+	Reason   string
+	Started  time.Time
+	Duration time.Duration
+	Tasks    []TaskReport
+}
+
+// Overran returns all reports of tasks which did not finish within their
+// phase timeout.
+func (r Report) Overran() []TaskReport {
+	var tasks []TaskReport
+	for _, task := range r.Tasks {
+		if task.Overran {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// Failed returns all reports of tasks which returned an error.
+func (r Report) Failed() []TaskReport {
+	var tasks []TaskReport
+	for _, task := range r.Tasks {
+		if task.Err != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+//*****************************************************************************
+// Shutdown
+//*****************************************************************************
+
+type shutdownTask struct {
+	name string
+	fn   TaskFunc
+}
+
+type shutdownPhase struct {
+	Phase
+	tasks []shutdownTask
+}
+
+// Shutdown implements a coordinated shutdown of a system, where registered tasks
+// are run phase after phase, each phase given a timeout for it's tasks, which are
+// reported as overran when exceeded. A Shutdown only runs once, either triggered
+// programmatically through Shutdown.Run or by a signal through Shutdown.AwaitSignals.
+type Shutdown struct {
+	ml      sync.Mutex
+	phases  []*shutdownPhase
+	started bool
+
+	once   sync.Once
+	done   chan struct{}
+	report Report
+}
+
+// NewShutdown returns a new Shutdown using provided phases in order, the
+// DefaultPhases are used if none is provided.
+func NewShutdown(phases ...Phase) *Shutdown {
+	if len(phases) == 0 {
+		phases = DefaultPhases()
+	}
+
+	var sh Shutdown
+	sh.done = make(chan struct{})
+	for _, phase := range phases {
+		if phase.Timeout <= 0 {
+			phase.Timeout = defaultPhaseTimeout
+		}
+		sh.phases = append(sh.phases, &shutdownPhase{Phase: phase})
+	}
+	return &sh
+}
+
+// AddTask registers a named task to be run during giving phase. It returns
+// an error if phase does not exist or shutdown has already started.
+func (s *Shutdown) AddTask(phase string, name string, fn TaskFunc) error {
+	s.ml.Lock()
+	defer s.ml.Unlock()
+
+	if s.started {
+		return errors.Wrap(ErrShutdownStarted, "Failed to add task %q", name)
+	}
+
+	for _, item := range s.phases {
+		if item.Name == phase {
+			item.tasks = append(item.tasks, shutdownTask{name: name, fn: fn})
+			return nil
+		}
+	}
+	return errors.Wrap(ErrUnknownPhase, "Phase %q not found", phase)
+}
+
+// Done returns a channel which is closed once shutdown has finished.
+func (s *Shutdown) Done() <-chan struct{} {
+	return s.done
+}
+
+// Run runs all phases of shutdown in order, blocking till all are done,
+// returning a report of the run. Calling Run again or concurrently will
+// block till the first run is finished, returning it's report.
+func (s *Shutdown) Run(reason string) Report {
+	s.once.Do(func() {
+		s.ml.Lock()
+		s.started = true
+		s.ml.Unlock()
+
+		s.report = s.run(reason)
+		close(s.done)
+	})
+
+	<-s.done
+	return s.report
+}
+
+// AwaitSignals blocks till one of provided signals is received, running the
+// shutdown with signal as reason. If no signal is provided, it waits for ctrl-c
+// or a SIGTERM, SIGINT or SIGQUIT signal. It returns if shutdown is started
+// programmatically before any signal is received.
+func (s *Shutdown) AwaitSignals(signals ...os.Signal) Report {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+
+	select {
+	case sig := <-ch:
+		return s.Run(sig.String())
+	case <-s.done:
+		return s.report
+	}
+}
+
+func (s *Shutdown) run(reason string) Report {
+	var report Report
+	report.Reason = reason
+	report.Started = time.Now()
+
+	for _, phase := range s.phases {
+		report.Tasks = append(report.Tasks, s.runPhase(phase)...)
+	}
+
+	report.Duration = time.Since(report.Started)
+	return report
+}
+
+func (s *Shutdown) runPhase(phase *shutdownPhase) []TaskReport {
+	ctx, cancel := context.WithTimeout(context.Background(), phase.Timeout)
+	defer cancel()
+
+	reports := make([]TaskReport, len(phase.tasks))
+
+	var waiter sync.WaitGroup
+	waiter.Add(len(phase.tasks))
+
+	for index, task := range phase.tasks {
+		go func(index int, task shutdownTask) {
+			defer waiter.Done()
+			reports[index] = runTask(ctx, phase.Name, task)
+		}(index, task)
+	}
+
+	waiter.Wait()
+	return reports
+}
+
+// runTask runs giving task, returning once task is finished or context is done,
+// where task is left to finish in the background and reported as overran.
+func runTask(ctx context.Context, phase string, task shutdownTask) TaskReport {
+	report := TaskReport{Phase: phase, Task: task.name}
+	started := time.Now()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- task.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		report.Err = err
+	case <-ctx.Done():
+		report.Overran = true
+		report.Err = errors.Wrap(ctx.Err(), "Task %q overran phase %q", task.name, phase)
+	}
+
+	report.Duration = time.Since(started)
+	return report
+}
+
+//*****************************************************************************
+// Actor Tasks
+//*****************************************************************************
+
+// StopActorTask returns a TaskFunc which gracefully stops provided actor.
+func StopActorTask(actor actorkit.Actor) TaskFunc {
+	return func(_ context.Context) error {
+		return actor.Stop()
+	}
+}
+
+// KillActorTask returns a TaskFunc which kills provided actor.
+func KillActorTask(actor actorkit.Actor) TaskFunc {
+	return func(_ context.Context) error {
+		return actor.Kill()
+	}
+}
+
+// DestroyActorTask returns a TaskFunc which destroys provided actor.
+func DestroyActorTask(actor actorkit.Actor) TaskFunc {
+	return func(_ context.Context) error {
+		return actor.Destroy()
+	}
+}
+
+// PoisonAddrTask returns a TaskFunc which gracefully stops the actor of
+// provided address.
+func PoisonAddrTask(addr actorkit.Addr) TaskFunc {
+	return func(_ context.Context) error {
+		return actorkit.Poison(addr)
+	}
+}
+
+// DestroyAddrTask returns a TaskFunc which destroys the actor of provided
+// address.
+func DestroyAddrTask(addr actorkit.Addr) TaskFunc {
+	return func(_ context.Context) error {
+		return actorkit.Destroy(addr)
+	}
+}
@@ -0,0 +1,78 @@
+package platform_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/platform"
+)
+
+func TestShutdownRunsPhasesInOrder(t *testing.T) {
+	shutdown := platform.NewShutdown()
+
+	var ml sync.Mutex
+	var order []string
+	record := func(name string) platform.TaskFunc {
+		return func(_ context.Context) error {
+			ml.Lock()
+			order = append(order, name)
+			ml.Unlock()
+			return nil
+		}
+	}
+
+	require.NoError(t, shutdown.AddTask(platform.PhaseCloseBrokers, "brokers", record("brokers")))
+	require.NoError(t, shutdown.AddTask(platform.PhaseFlushLogs, "logs", record("logs")))
+	require.NoError(t, shutdown.AddTask(platform.PhaseStopTraffic, "http", record("http")))
+	require.Error(t, shutdown.AddTask("unknown", "task", record("unknown")))
+
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown.AddTask(platform.PhaseStopActors, "system", platform.DestroyAddrTask(system)))
+
+	report := shutdown.Run("test")
+	require.Equal(t, "test", report.Reason)
+	require.Len(t, report.Tasks, 4)
+	require.Empty(t, report.Failed())
+	require.Equal(t, []string{"http", "logs", "brokers"}, order)
+	require.Equal(t, actorkit.DESTROYED, system.State())
+
+	require.Error(t, shutdown.AddTask(platform.PhaseStopTraffic, "late", record("late")))
+	require.Equal(t, report, shutdown.Run("again"))
+}
+
+func TestShutdownReportsOverranTasks(t *testing.T) {
+	shutdown := platform.NewShutdown(
+		platform.Phase{Name: "first", Timeout: 50 * time.Millisecond},
+		platform.Phase{Name: "second", Timeout: 50 * time.Millisecond},
+	)
+
+	require.NoError(t, shutdown.AddTask("first", "slow", func(_ context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+	require.NoError(t, shutdown.AddTask("first", "fast", func(_ context.Context) error {
+		return nil
+	}))
+	require.NoError(t, shutdown.AddTask("second", "failing", func(_ context.Context) error {
+		return errors.New("bad")
+	}))
+
+	report := shutdown.Run("test")
+	require.Len(t, report.Overran(), 1)
+	require.Equal(t, "slow", report.Overran()[0].Task)
+	require.Len(t, report.Failed(), 2)
+	require.True(t, report.Duration < time.Second)
+
+	select {
+	case <-shutdown.Done():
+	default:
+		require.Fail(t, "expected shutdown to be done")
+	}
+}
@@ -0,0 +1,341 @@
+package actorkit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gokit/errors"
+)
+
+// ErrRateLimited is returned when a message exceeds the rate limit of a RateLimitAddr.
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+//***********************************************************
+// RateLimit
+//***********************************************************
+
+// RatePolicy defines the handling of messages which exceed a rate limit.
+type RatePolicy int
+
+// constants of rate policies.
+const (
+	// DelayRate delays delivery of excess messages till their rate allows,
+	// blocking the sender.
+	DelayRate RatePolicy = iota
+
+	// RejectRate rejects excess messages with ErrRateLimited.
+	RejectRate
+
+	// DeadLetterRate rejects excess messages with ErrRateLimited, delivering
+	// them to the dead letters with the RateLimitedReason.
+	DeadLetterRate
+)
+
+// RateLimit defines a token bucket rate, where Rate tokens are added every Per
+// duration into a bucket holding at most Burst tokens.
+type RateLimit struct {
+	// Rate sets the tokens added to the bucket every Per duration. A zero Rate
+	// disables limiting.
+	Rate int
+
+	// Per sets the duration within which Rate tokens are added.
+	//
+	// Defaults to 1 second.
+	Per time.Duration
+
+	// Burst sets the maximum tokens within the bucket.
+	//
+	// Defaults to Rate.
+	Burst int
+}
+
+func (rl RateLimit) init() RateLimit {
+	if rl.Per <= 0 {
+		rl.Per = time.Second
+	}
+
+	if rl.Burst <= 0 {
+		rl.Burst = rl.Rate
+	}
+	return rl
+}
+
+// perNanosecond returns the tokens added to the bucket every nanosecond.
+func (rl RateLimit) perNanosecond() float64 {
+	return float64(rl.Rate) / float64(rl.Per.Nanoseconds())
+}
+
+// RateLimitConfig defines configuration values which will be used
+// by RateLimiter for it's operations.
+type RateLimitConfig struct {
+	// Limit sets the RateLimit used for all keys without a limit
+	// within Limits.
+	Limit RateLimit
+
+	// Limits sets the RateLimit for specific keys.
+	Limits map[string]RateLimit
+
+	// Key sets the function used to derive the key of a envelope, where every
+	// key is limited by it's own bucket.
+	//
+	// Defaults to a function returning the same key for all envelopes.
+	Key func(Envelope) string
+
+	// Policy sets the handling of envelopes exceeding their rate.
+	//
+	// Defaults to DelayRate.
+	Policy RatePolicy
+
+	// MaxDelay sets the maximum delay of a envelope by the DelayRate policy,
+	// after which it is rejected with ErrRateLimited.
+	//
+	// Defaults to 0, where envelopes are delayed for as long as required.
+	MaxDelay time.Duration
+
+	// Clock sets the Clock used for refilling buckets and delaying envelopes.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
+
+	// DeadLetters sets the DeadLetter receiving envelopes rejected by the
+	// DeadLetterRate policy.
+	//
+	// Defaults to the global dead letters.
+	DeadLetters DeadLetter
+}
+
+func (rc *RateLimitConfig) init() {
+	rc.Limit = rc.Limit.init()
+
+	limits := make(map[string]RateLimit, len(rc.Limits))
+	for key, limit := range rc.Limits {
+		limits[key] = limit.init()
+	}
+	rc.Limits = limits
+
+	if rc.Key == nil {
+		rc.Key = func(Envelope) string {
+			return ""
+		}
+	}
+
+	if rc.Clock == nil {
+		rc.Clock = SystemClock{}
+	}
+
+	if rc.DeadLetters == nil {
+		rc.DeadLetters = eventDeathMails
+	}
+}
+
+// HeaderKey returns a function for RateLimitConfig.Key which derives the key
+// of a envelope from the value of giving header.
+func HeaderKey(name string) func(Envelope) string {
+	return func(env Envelope) string {
+		return env.Header.Get(name)
+	}
+}
+
+//***********************************************************
+// RateLimiter
+//***********************************************************
+
+// bucket holds the tokens of a key.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter implements a token bucket rate limiter, which limits envelopes
+// by the key derived from them.
+type RateLimiter struct {
+	config RateLimitConfig
+
+	bl      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRateLimiter returns a new instance of RateLimiter.
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	config.init()
+
+	return &RateLimiter{
+		config:  config,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Allow returns true/false if giving envelope is within it's rate, taking a
+// token from it's bucket if so.
+func (rl *RateLimiter) Allow(env Envelope) bool {
+	_, ok := rl.reserve(rl.config.Key(env), false, 0)
+	return ok
+}
+
+// Wait blocks till giving envelope is within it's rate if the policy of the
+// RateLimiter is DelayRate, returning an error if the delay exceeds the MaxDelay
+// of the RateLimiter or the envelope's context is done. For other policies, it
+// returns an error if the envelope is not within it's rate.
+func (rl *RateLimiter) Wait(env Envelope) error {
+	key := rl.config.Key(env)
+
+	wait, ok := rl.reserve(key, rl.config.Policy == DelayRate, rl.config.MaxDelay)
+	if !ok {
+		return errors.Wrap(ErrRateLimited, "Key %q exceeded rate limit", key)
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	ready := make(chan struct{})
+	timer := rl.config.Clock.AfterFunc(wait, func() {
+		close(ready)
+	})
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return nil
+	case <-env.Context().Done():
+		rl.cancel(key)
+		return errors.Wrap(env.Context().Err(), "Rate limit wait cancelled")
+	}
+}
+
+// reserve takes a token from the bucket of giving key, returning the duration to
+// wait for the token to be available. It returns false if the token is not
+// available now when wait is false, or within provided max duration if above 0.
+func (rl *RateLimiter) reserve(key string, wait bool, max time.Duration) (time.Duration, bool) {
+	limit := rl.limitOf(key)
+	if limit.Rate <= 0 {
+		return 0, true
+	}
+
+	now := rl.config.Clock.Now()
+
+	rl.bl.Lock()
+	defer rl.bl.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		rl.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed.Nanoseconds()) * limit.perNanosecond()
+		if b.tokens > float64(limit.Burst) {
+			b.tokens = float64(limit.Burst)
+		}
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+
+	delay := time.Duration((1 - b.tokens) / limit.perNanosecond())
+	if !wait || (max > 0 && delay > max) {
+		return delay, false
+	}
+
+	b.tokens--
+	return delay, true
+}
+
+// cancel returns a token reserved for giving key.
+func (rl *RateLimiter) cancel(key string) {
+	rl.bl.Lock()
+	if b, ok := rl.buckets[key]; ok {
+		b.tokens++
+	}
+	rl.bl.Unlock()
+}
+
+func (rl *RateLimiter) limitOf(key string) RateLimit {
+	if limit, ok := rl.config.Limits[key]; ok {
+		return limit
+	}
+	return rl.config.Limit
+}
+
+//***********************************************************
+// RateLimitAddr
+//***********************************************************
+
+// RateLimitAddr implements a rate limiting Addr wrapper, which limits the rate of
+// message delivery to a giving origin address, where excess messages are delayed,
+// rejected or dead-lettered in accordance with the RatePolicy of it's configuration.
+type RateLimitAddr struct {
+	addr    Addr
+	limiter *RateLimiter
+
+	// Fallback defines function to be called as fallback
+	// when giving envelope is rejected due to it's rate.
+	fallback func(error, Envelope) error
+}
+
+// NewRateLimitAddr returns a new instance of a RateLimitAddr.
+func NewRateLimitAddr(addr Addr, config RateLimitConfig, fallback func(error, Envelope) error) *RateLimitAddr {
+	return &RateLimitAddr{
+		addr:     addr,
+		fallback: fallback,
+		limiter:  NewRateLimiter(config),
+	}
+}
+
+// Forward attempts to forward giving envelope to underline address.
+// It returns an error if giving envelope is rejected due to it's rate, hence
+// passing envelope to fallback if provided.
+func (dm *RateLimitAddr) Forward(env Envelope) error {
+	if err := dm.limiter.Wait(env); err != nil {
+		return dm.reject(err, env)
+	}
+	return dm.addr.Forward(env)
+}
+
+// Send delivers giving data as a envelope to provided underline address.
+// It returns an error if giving envelope is rejected due to it's rate, hence
+// passing envelope to fallback if provided.
+func (dm *RateLimitAddr) Send(data interface{}, addr Addr) error {
+	env := CreateEnvelope(addr, Header{}, data)
+	if err := dm.limiter.Wait(env); err != nil {
+		return dm.reject(err, env)
+	}
+	return dm.addr.Send(data, addr)
+}
+
+// SendWithHeader delivers data as a enveloped with attached headers to underline
+// address.
+// It returns an error if giving envelope is rejected due to it's rate, hence
+// passing envelope to fallback if provided.
+func (dm *RateLimitAddr) SendWithHeader(data interface{}, h Header, addr Addr) error {
+	env := CreateEnvelope(addr, h, data)
+	if err := dm.limiter.Wait(env); err != nil {
+		return dm.reject(err, env)
+	}
+	return dm.addr.SendWithHeader(data, h, addr)
+}
+
+// Limiter returns the underline RateLimiter of address.
+func (dm *RateLimitAddr) Limiter() *RateLimiter {
+	return dm.limiter
+}
+
+func (dm *RateLimitAddr) reject(err error, env Envelope) error {
+	if dm.limiter.config.Policy == DeadLetterRate {
+		dm.limiter.config.DeadLetters.RecoverMail(DeadMail{
+			To:      dm.addr,
+			Message: env,
+			Reason:  RateLimitedReason,
+			Time:    dm.limiter.config.Clock.Now(),
+		})
+	}
+
+	if dm.fallback == nil {
+		return err
+	}
+	return dm.fallback(err, env)
+}
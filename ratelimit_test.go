@@ -0,0 +1,96 @@
+package actorkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitAddrRejectsPerKey(t *testing.T) {
+	clock := &manualClock{now: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 10)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	var rejected []string
+	addr := actorkit.NewRateLimitAddr(actorkit.AddressOf(am, "basic"), actorkit.RateLimitConfig{
+		Limit:  actorkit.RateLimit{Rate: 1, Per: time.Hour},
+		Limits: map[string]actorkit.RateLimit{"gold": {Rate: 2, Per: time.Hour}},
+		Key:    actorkit.HeaderKey("tenant"),
+		Policy: actorkit.RejectRate,
+		Clock:  clock,
+	}, func(err error, env actorkit.Envelope) error {
+		rejected = append(rejected, env.Header.Get("tenant"))
+		return err
+	})
+
+	basicTenant := actorkit.Header{"tenant": "basic"}
+	goldTenant := actorkit.Header{"tenant": "gold"}
+
+	require.NoError(t, addr.SendWithHeader(1, basicTenant, nil))
+	require.Error(t, addr.SendWithHeader(2, basicTenant, nil))
+
+	require.NoError(t, addr.SendWithHeader(3, goldTenant, nil))
+	require.NoError(t, addr.SendWithHeader(4, goldTenant, nil))
+	require.Error(t, addr.SendWithHeader(5, goldTenant, nil))
+
+	require.Equal(t, []string{"basic", "gold"}, rejected)
+
+	clock.Advance(time.Hour)
+	require.NoError(t, addr.SendWithHeader(6, basicTenant, nil))
+
+	for _, expected := range []int{1, 3, 4, 6} {
+		require.Equal(t, expected, (<-base.Message).Data)
+	}
+}
+
+func TestRateLimitAddrDeadLetters(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 10)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	addr := actorkit.NewRateLimitAddr(actorkit.AddressOf(am, "basic"), actorkit.RateLimitConfig{
+		Limit:       actorkit.RateLimit{Rate: 1, Per: time.Hour},
+		Policy:      actorkit.DeadLetterRate,
+		DeadLetters: office,
+	}, nil)
+
+	require.NoError(t, addr.Send(1, nil))
+	require.Error(t, addr.Send(2, nil))
+	require.Error(t, addr.Forward(actorkit.CreateEnvelope(nil, actorkit.Header{}, 3)))
+
+	require.Equal(t, int64(2), office.Count(actorkit.RateLimitedReason))
+	require.Equal(t, "RATE_LIMITED", actorkit.RateLimitedReason.String())
+}
+
+func TestRateLimitAddrDelays(t *testing.T) {
+	base := &basic{Message: make(chan *actorkit.Envelope, 10)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	addr := actorkit.NewRateLimitAddr(actorkit.AddressOf(am, "basic"), actorkit.RateLimitConfig{
+		Limit: actorkit.RateLimit{Rate: 1, Per: 50 * time.Millisecond},
+	}, nil)
+
+	start := time.Now()
+	require.NoError(t, addr.Send(1, nil))
+	require.NoError(t, addr.Send(2, nil))
+	require.NoError(t, addr.Send(3, nil))
+	require.True(t, time.Since(start) >= 90*time.Millisecond)
+
+	limited := actorkit.NewRateLimitAddr(actorkit.AddressOf(am, "basic"), actorkit.RateLimitConfig{
+		Limit:    actorkit.RateLimit{Rate: 1, Per: time.Hour},
+		MaxDelay: 10 * time.Millisecond,
+	}, nil)
+
+	require.NoError(t, limited.Send(4, nil))
+	require.Error(t, limited.Send(5, nil))
+}
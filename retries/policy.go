@@ -0,0 +1,177 @@
+package retries
+
+import (
+	"context"
+	"time"
+)
+
+//***************************************************************
+// Policy
+//***************************************************************
+
+// DefaultMaxAttempts defines the attempts made by a Policy with neither
+// MaxAttempts nor MaxDuration set.
+const DefaultMaxAttempts = 3
+
+// Sleeper defines a function which blocks for giving duration, returning
+// an error if giving context is done before then.
+type Sleeper func(ctx context.Context, dur time.Duration) error
+
+// ContextSleep implements the Sleeper type using a timer.
+func ContextSleep(ctx context.Context, dur time.Duration) error {
+	if dur <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(dur)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Attempt holds details of a single attempt made by a Policy.
+type Attempt struct {
+	// Number is the number of attempt, starting from 1.
+	Number int
+
+	// Err is the error returned by attempt, nil if it succeeded.
+	Err error
+
+	// Elapsed is the duration since the first attempt started.
+	Elapsed time.Duration
+
+	// Delay is the duration before the next attempt, which is zero
+	// if no further attempt will be made.
+	Delay time.Duration
+
+	// Last is true if no further attempt will be made.
+	Last bool
+}
+
+// Policy defines a retry policy, which retries a function with a backoff delay
+// between attempts till it succeeds, returns a permanent error, it's attempts
+// or duration are exhausted or it's context is done.
+type Policy struct {
+	// Backoff sets the function returning the delay before the next attempt,
+	// giving it the number of failed attempts. Any of the backoff functions
+	// of the package can be used.
+	//
+	// Defaults to no delay.
+	Backoff func(int) time.Duration
+
+	// MaxAttempts sets the maximum attempts including the first attempt. A
+	// negative value allows unlimited attempts, which are then limited by
+	// MaxDuration or the context alone.
+	//
+	// Defaults to DefaultMaxAttempts if MaxDuration is not set, else to
+	// unlimited attempts.
+	MaxAttempts int
+
+	// MaxDuration sets the maximum duration from the first attempt after which
+	// no further attempt is made.
+	//
+	// Defaults to 0, where attempts are limited by MaxAttempts or the context.
+	MaxDuration time.Duration
+
+	// IsPermanent sets the function used to verify if a giving error is
+	// permanent, hence not retried.
+	//
+	// Defaults to a function that always returns false.
+	IsPermanent func(error) bool
+
+	// OnAttempt sets giving callback to be called after every attempt,
+	// which can be used for logging and metrics.
+	OnAttempt func(Attempt)
+
+	// Sleep sets the Sleeper used for waiting between attempts.
+	//
+	// Defaults to ContextSleep.
+	Sleep Sleeper
+
+	// Now sets the function used to retrieve current time.
+	//
+	// Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Do runs giving function in accordance with the policy, returning nil on the
+// first successful attempt, else the error of the last attempt. If the context
+// is done while waiting between attempts, then the context's error is returned.
+func (p Policy) Do(ctx context.Context, fn func(context.Context) error) error {
+	now := p.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	sleep := p.Sleep
+	if sleep == nil {
+		sleep = ContextSleep
+	}
+
+	start := now()
+	for number := 1; ; number++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn(ctx)
+
+		attempt := Attempt{Number: number, Err: err, Elapsed: now().Sub(start)}
+		if err == nil || !p.retryable(err, number) {
+			attempt.Last = true
+			p.notify(attempt)
+			return err
+		}
+
+		if p.Backoff != nil {
+			attempt.Delay = p.Backoff(number)
+		}
+
+		if p.MaxDuration > 0 && attempt.Elapsed+attempt.Delay > p.MaxDuration {
+			attempt.Last = true
+			attempt.Delay = 0
+			p.notify(attempt)
+			return err
+		}
+
+		p.notify(attempt)
+
+		if serr := sleep(ctx, attempt.Delay); serr != nil {
+			return serr
+		}
+	}
+}
+
+// DoFunc runs giving function without a context in accordance with the policy.
+func (p Policy) DoFunc(fn func() error) error {
+	return p.Do(context.Background(), func(context.Context) error {
+		return fn()
+	})
+}
+
+func (p Policy) retryable(err error, number int) bool {
+	if p.IsPermanent != nil && p.IsPermanent(err) {
+		return false
+	}
+
+	switch {
+	case p.MaxAttempts < 0:
+		return true
+	case p.MaxAttempts > 0:
+		return number < p.MaxAttempts
+	case p.MaxDuration > 0:
+		return true
+	}
+	return number < DefaultMaxAttempts
+}
+
+func (p Policy) notify(attempt Attempt) {
+	if p.OnAttempt != nil {
+		p.OnAttempt(attempt)
+	}
+}
@@ -0,0 +1,111 @@
+package retries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit/retries"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyRetriesTillSuccess(t *testing.T) {
+	var slept []time.Duration
+	var attempts []retries.Attempt
+
+	policy := retries.Policy{
+		Backoff:     retries.LinearBackOff,
+		MaxAttempts: 5,
+		OnAttempt: func(attempt retries.Attempt) {
+			attempts = append(attempts, attempt)
+		},
+		Sleep: func(ctx context.Context, dur time.Duration) error {
+			slept = append(slept, dur)
+			return nil
+		},
+	}
+
+	var calls int
+	require.NoError(t, policy.DoFunc(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("bad")
+		}
+		return nil
+	}))
+
+	require.Equal(t, 3, calls)
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, slept)
+	require.Len(t, attempts, 3)
+	require.Error(t, attempts[0].Err)
+	require.False(t, attempts[0].Last)
+	require.NoError(t, attempts[2].Err)
+	require.True(t, attempts[2].Last)
+	require.Equal(t, 3, attempts[2].Number)
+}
+
+func TestPolicyStops(t *testing.T) {
+	noSleep := func(ctx context.Context, dur time.Duration) error { return nil }
+	bad := errors.New("bad")
+
+	var calls int
+	failing := func(ctx context.Context) error {
+		calls++
+		return bad
+	}
+
+	// max attempts.
+	require.Equal(t, bad, retries.Policy{MaxAttempts: 3, Sleep: noSleep}.Do(context.Background(), failing))
+	require.Equal(t, 3, calls)
+
+	// permanent errors.
+	calls = 0
+	require.Equal(t, bad, retries.Policy{
+		Sleep: noSleep,
+		IsPermanent: func(err error) bool {
+			return err == bad
+		},
+	}.Do(context.Background(), failing))
+	require.Equal(t, 1, calls)
+
+	// max duration.
+	calls = 0
+	now := time.Now()
+	require.Equal(t, bad, retries.Policy{
+		Backoff:     retries.LinearBackOff,
+		MaxDuration: 5 * time.Second,
+		Now: func() time.Time {
+			return now
+		},
+		Sleep: func(ctx context.Context, dur time.Duration) error {
+			now = now.Add(dur)
+			return nil
+		},
+	}.Do(context.Background(), failing))
+	require.Equal(t, 3, calls)
+
+	// context cancellation.
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	err := retries.Policy{
+		Backoff:     func(int) time.Duration { return time.Hour },
+		MaxAttempts: -1,
+		OnAttempt: func(retries.Attempt) {
+			cancel()
+		},
+	}.Do(ctx, failing)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestPolicyZeroValueIsBounded(t *testing.T) {
+	var calls int
+	err := retries.Policy{}.DoFunc(func() error {
+		calls++
+		return errors.New("bad")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, retries.DefaultMaxAttempts, calls)
+}
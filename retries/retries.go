@@ -3,6 +3,7 @@ package retries
 import (
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -35,10 +36,31 @@ func LinearDoUntil(fx func() error, total int, elapse time.Duration) error {
 //***************************************************************
 
 var (
-	// random is used to generate pseudo-random numbers.
-	random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// random is used to generate pseudo-random numbers, it's source is
+	// guarded as backoffs are generated concurrently.
+	random = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano())})
 )
 
+// lockedSource implements a rand.Source safe for concurrent use.
+type lockedSource struct {
+	ml  sync.Mutex
+	src rand.Source
+}
+
+// Int63 implements the rand.Source interface.
+func (ls *lockedSource) Int63() int64 {
+	ls.ml.Lock()
+	defer ls.ml.Unlock()
+	return ls.src.Int63()
+}
+
+// Seed implements the rand.Source interface.
+func (ls *lockedSource) Seed(seed int64) {
+	ls.ml.Lock()
+	defer ls.ml.Unlock()
+	ls.src.Seed(seed)
+}
+
 // LinearBackOff returns increasing durations, each a second longer than the last
 func LinearBackOff(i int) time.Duration {
 	return time.Duration(i) * time.Second
@@ -0,0 +1,26 @@
+package retries_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit/retries"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitterBackOffIsConcurrentSafe(t *testing.T) {
+	var waiter sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			for attempt := 0; attempt < 100; attempt++ {
+				delay := retries.ExponentialJitterBackOff(attempt % 4)
+				require.True(t, delay > 0)
+				require.True(t, delay < 11*time.Second)
+			}
+		}()
+	}
+	waiter.Wait()
+}
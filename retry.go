@@ -0,0 +1,237 @@
+package actorkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/gokit/actorkit/retries"
+	"github.com/gokit/errors"
+)
+
+//***********************************************************
+// RetryAddr
+//***********************************************************
+
+// RetryConfig defines configuration values which will be used
+// by RetryAddr for it's operations.
+type RetryConfig struct {
+	// Policy sets the retries.Policy deciding the attempts, delays and
+	// permanent errors of a delivery, where Policy.Sleep and Policy.Now
+	// default to using Clock.
+	//
+	// Policy.Backoff defaults to retries.RangedExponential(100ms, 10s).
+	Policy retries.Policy
+
+	// Clock sets the Clock used for delaying retries.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
+
+	// DeadLetters sets the DeadLetter receiving envelopes whose delivery
+	// failed after all attempts.
+	//
+	// Defaults to the global dead letters.
+	DeadLetters DeadLetter
+}
+
+func (rc *RetryConfig) init() {
+	if rc.Clock == nil {
+		rc.Clock = SystemClock{}
+	}
+
+	if rc.DeadLetters == nil {
+		rc.DeadLetters = eventDeathMails
+	}
+
+	if rc.Policy.Backoff == nil {
+		rc.Policy.Backoff = retries.RangedExponential(100*time.Millisecond, 10*time.Second)
+	}
+
+	if rc.Policy.Sleep == nil {
+		rc.Policy.Sleep = clockSleep(rc.Clock)
+	}
+
+	if rc.Policy.Now == nil {
+		rc.Policy.Now = rc.Clock.Now
+	}
+}
+
+// clockSleep returns a retries.Sleeper waiting on provided Clock.
+func clockSleep(clock Clock) retries.Sleeper {
+	return func(ctx context.Context, dur time.Duration) error {
+		if dur <= 0 {
+			return ctx.Err()
+		}
+
+		ready := make(chan struct{})
+		timer := clock.AfterFunc(dur, func() {
+			close(ready)
+		})
+		defer timer.Stop()
+
+		select {
+		case <-ready:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RetryAddr implements a retrying Addr wrapper, which retries failed deliveries
+// to a giving origin address with a backoff delay between attempts, where
+// envelopes whose delivery still failed after all attempts are delivered to
+// the dead letters with the RetriesExhaustedReason. Permanent errors of the
+// policy are returned without retries, while envelopes whose context is
+// done while awaiting a retry are delivered to the dead letters with the
+// DeadlineExceededReason, returning the context's error.
+//
+// Forward, Send and SendWithHeader block the sender till delivery succeeds
+// or attempts are exhausted. Ask retries requests whose futures are rejected,
+// without blocking the sender.
+type RetryAddr struct {
+	addr   Addr
+	config RetryConfig
+}
+
+// NewRetryAddr returns a new instance of a RetryAddr.
+func NewRetryAddr(addr Addr, config RetryConfig) *RetryAddr {
+	config.init()
+
+	return &RetryAddr{
+		addr:   addr,
+		config: config,
+	}
+}
+
+// Forward attempts to forward giving envelope to underline address, retrying
+// failed attempts.
+func (dm *RetryAddr) Forward(env Envelope) error {
+	return dm.retry(env, func() error {
+		return dm.addr.Forward(recoverable(env))
+	})
+}
+
+// Send delivers giving data as a envelope to provided underline address,
+// retrying failed attempts.
+func (dm *RetryAddr) Send(data interface{}, addr Addr) error {
+	return dm.Forward(CreateEnvelope(addr, Header{}, data))
+}
+
+// SendWithHeader delivers data as a enveloped with attached headers to underline
+// address, retrying failed attempts.
+func (dm *RetryAddr) SendWithHeader(data interface{}, h Header, addr Addr) error {
+	return dm.Forward(CreateEnvelope(addr, h, data))
+}
+
+// Ask delivers data with attached headers to underline address using a new timed
+// Future per attempt as sender, retrying attempts whose delivery failed or whose
+// future was rejected or timed out.
+//
+// The returned Future is resolved with the first successful reply, else rejected
+// with the last error once attempts are exhausted. Rejecting the returned Future,
+// e.g by escalating it, stops further attempts.
+func (dm *RetryAddr) Ask(data interface{}, h Header, timeout time.Duration) (Future, error) {
+	return dm.AskContext(context.Background(), data, h, timeout)
+}
+
+// AskContext works like Ask, where further attempts are stopped once provided
+// context is done, rejecting the returned Future with the context's error. It
+// returns an error if the context is already done.
+func (dm *RetryAddr) AskContext(ctx context.Context, data interface{}, h Header, timeout time.Duration) (Future, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "Failed to ask %q", dm.addr.Addr())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	result := NewFuture(dm.addr)
+	result.PipeAction(func(Envelope) {
+		cancel()
+	})
+
+	env := CreateEnvelope(result, h, data).WithContext(ctx)
+
+	go func() {
+		var reply Envelope
+		err := dm.retry(env, func() error {
+			attempt := TimedFuture(dm.addr, timeout)
+
+			request := recoverable(env)
+			request.Sender = attempt
+			if err := dm.addr.Forward(request); err != nil {
+				return err
+			}
+
+			if err := attempt.Wait(); err != nil {
+				return err
+			}
+
+			reply = attempt.Result()
+			return nil
+		})
+
+		if err != nil {
+			result.Escalate(err)
+			return
+		}
+		result.Forward(reply)
+	}()
+
+	return result, nil
+}
+
+func (dm *RetryAddr) retry(env Envelope, fn func() error) error {
+	policy := dm.config.Policy
+
+	var last retries.Attempt
+	policy.OnAttempt = func(attempt retries.Attempt) {
+		last = attempt
+		if dm.config.Policy.OnAttempt != nil {
+			dm.config.Policy.OnAttempt(attempt)
+		}
+	}
+
+	err := policy.Do(env.Context(), func(context.Context) error {
+		return fn()
+	})
+
+	switch {
+	case err == nil:
+		return nil
+	case !last.Last:
+		return dm.cancelled(env)
+	case policy.IsPermanent != nil && policy.IsPermanent(err):
+		return err
+	}
+
+	dm.config.DeadLetters.RecoverMail(DeadMail{
+		To:      dm.addr,
+		Message: env,
+		Reason:  RetriesExhaustedReason,
+		Time:    dm.config.Clock.Now(),
+	})
+
+	return errors.Wrap(err, "Retries exhausted for delivery to %q", dm.addr.Addr())
+}
+
+// recoverable returns a copy of envelope which a receiver rejecting it does
+// not deliver to the dead letters, as it's sender handles the failure, such
+// as RetryAddr once retries end or DeadLetterOffice.Redeliver keeping it.
+func recoverable(env Envelope) Envelope {
+	env.recovered = true
+	return env
+}
+
+// cancelled delivers envelope whose context was done while awaiting a retry to
+// the dead letters with the DeadlineExceededReason, returning the context's error.
+func (dm *RetryAddr) cancelled(env Envelope) error {
+	dm.config.DeadLetters.RecoverMail(DeadMail{
+		To:      dm.addr,
+		Message: env,
+		Reason:  DeadlineExceededReason,
+		Time:    dm.config.Clock.Now(),
+	})
+
+	return errors.Wrap(env.Context().Err(), "Retries cancelled for delivery to %q", dm.addr.Addr())
+}
@@ -0,0 +1,260 @@
+package actorkit_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/retries"
+	"github.com/gokit/actorkit/testkit"
+	kerrors "github.com/gokit/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAddrRetriesTillDelivered(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 2)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{
+		Behaviour: base,
+		Mailbox:   actorkit.BoundedBoxQueue(1, actorkit.DropNew, nil),
+	})
+	defer am.Destroy()
+
+	target := actorkit.AddressOf(am, "basic")
+	require.NoError(t, target.Send(1, nil))
+
+	var attempts []int
+	addr := actorkit.NewRetryAddr(target, actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 3,
+			Backoff: func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				if attempt == 2 {
+					require.NoError(t, am.Start())
+				}
+				return 10 * time.Millisecond
+			},
+		},
+	})
+
+	require.NoError(t, addr.Send(2, nil))
+	require.Equal(t, []int{1, 2}, attempts)
+	require.Equal(t, 1, (<-base.Message).Data)
+	require.Equal(t, 2, (<-base.Message).Data)
+	require.Equal(t, int64(0), office.Total())
+}
+
+func TestRetryAddrDeadLettersWhenExhausted(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, am.Start())
+	require.NoError(t, am.Kill())
+
+	var attempts int
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "basic"), actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 4,
+			Backoff: func(attempt int) time.Duration {
+				attempts++
+				return time.Millisecond
+			},
+		},
+	})
+
+	require.Error(t, addr.Send("hello", nil))
+	require.Equal(t, 3, attempts)
+	require.Equal(t, int64(1), office.Count(actorkit.RetriesExhaustedReason))
+
+	// elapsed time stops retries before attempts are exhausted.
+	attempts = 0
+	elapsed := actorkit.NewRetryAddr(actorkit.AddressOf(am, "basic"), actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 10,
+			MaxDuration: 25 * time.Millisecond,
+			Backoff: func(attempt int) time.Duration {
+				attempts++
+				return 10 * time.Millisecond
+			},
+		},
+	})
+
+	require.Error(t, elapsed.Send("hello", nil))
+	require.True(t, attempts < 10)
+	require.Equal(t, int64(2), office.Count(actorkit.RetriesExhaustedReason))
+
+	// errors which are not retryable are returned immediately.
+	attempts = 0
+	permanent := actorkit.NewRetryAddr(actorkit.AddressOf(am, "basic"), actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			IsPermanent: func(error) bool {
+				return true
+			},
+			Backoff: func(attempt int) time.Duration {
+				attempts++
+				return time.Millisecond
+			},
+		},
+	})
+
+	require.Error(t, permanent.Send("hello", nil))
+	require.Equal(t, 0, attempts)
+	require.Equal(t, int64(2), office.Count(actorkit.RetriesExhaustedReason))
+}
+
+func TestRetryAddrAskRetriesFailedFutures(t *testing.T) {
+	var received int32
+	am := actorkit.FromFunc("ns", "flaky", func(addr actorkit.Addr, env actorkit.Envelope) {
+		// first request is never answered.
+		if atomic.AddInt32(&received, 1) == 1 {
+			return
+		}
+		env.Sender.Send("pong", addr)
+	})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "flaky"), actorkit.RetryConfig{
+		DeadLetters: actorkit.NewDeadLetterOffice(10),
+		Policy: retries.Policy{
+			Backoff: func(attempt int) time.Duration {
+				return time.Millisecond
+			},
+		},
+	})
+
+	future, err := addr.Ask("ping", actorkit.Header{}, 20*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, future.Wait())
+	require.Equal(t, "pong", future.Result().Data)
+	require.Equal(t, int32(2), atomic.LoadInt32(&received))
+}
+
+func TestRetryAddrCancelledContext(t *testing.T) {
+	am := actorkit.NewActorImpl("ns", "dead", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, am.Start())
+	require.NoError(t, am.Kill())
+
+	office := actorkit.NewDeadLetterOffice(10)
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "dead"), actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 10,
+			Backoff: func(attempt int) time.Duration {
+				return time.Hour
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	err := addr.Forward(actorkit.CreateEnvelope(nil, actorkit.Header{}, "hello").WithContext(ctx))
+	require.Error(t, err)
+	require.True(t, kerrors.IsAny(err, context.Canceled))
+	require.Equal(t, int64(1), office.Count(actorkit.DeadlineExceededReason))
+	require.Equal(t, int64(0), office.Count(actorkit.RetriesExhaustedReason))
+
+	_, err = addr.AskContext(ctx, "ping", actorkit.Header{}, time.Second)
+	require.Error(t, err)
+}
+
+func TestRetryAddrAskStopsOnRejectedFuture(t *testing.T) {
+	var received int32
+	am := actorkit.FromFunc("ns", "silent", func(addr actorkit.Addr, env actorkit.Envelope) {
+		atomic.AddInt32(&received, 1)
+	})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "silent"), actorkit.RetryConfig{
+		DeadLetters: actorkit.NewDeadLetterOffice(10),
+		Policy: retries.Policy{
+			MaxAttempts: 100,
+			Backoff: func(attempt int) time.Duration {
+				return 5 * time.Millisecond
+			},
+		},
+	})
+
+	future, err := addr.Ask("ping", actorkit.Header{}, 5*time.Millisecond)
+	require.NoError(t, err)
+
+	for i := 0; i < 100 && atomic.LoadInt32(&received) < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	future.Escalate(errors.New("abandoned"))
+
+	time.Sleep(30 * time.Millisecond)
+	stopped := atomic.LoadInt32(&received)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, stopped, atomic.LoadInt32(&received))
+}
+
+func TestRetryAddrDeadLettersOnce(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: &basic{}, DeadLetters: office})
+	require.NoError(t, am.Start())
+	require.NoError(t, am.Kill())
+
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "basic"), actorkit.RetryConfig{
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 3,
+			Backoff: func(attempt int) time.Duration {
+				return time.Millisecond
+			},
+		},
+	})
+
+	require.Error(t, addr.Send("hello", nil))
+	require.Equal(t, int64(1), office.Total())
+	require.Equal(t, int64(1), office.Count(actorkit.RetriesExhaustedReason))
+
+	// envelopes sent without retries are still dead-lettered by the actor.
+	require.Error(t, actorkit.AddressOf(am, "basic").Send("direct", nil))
+	require.Equal(t, int64(1), office.Count(actorkit.ActorStoppedReason))
+}
+
+func TestRetryAddrWaitsOnClock(t *testing.T) {
+	am := actorkit.NewActorImpl("ns", "dead", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, am.Start())
+	require.NoError(t, am.Kill())
+
+	sch := testkit.NewScheduler(1)
+	office := actorkit.NewDeadLetterOffice(10)
+	addr := actorkit.NewRetryAddr(actorkit.AddressOf(am, "dead"), actorkit.RetryConfig{
+		Clock:       sch,
+		DeadLetters: office,
+		Policy: retries.Policy{
+			MaxAttempts: 2,
+			Backoff: func(attempt int) time.Duration {
+				return time.Hour
+			},
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- addr.Send("hello", nil)
+	}()
+
+	for i := 0; i < 200 && sch.Timers() == 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.Equal(t, 1, sch.Timers())
+	require.Len(t, done, 0)
+
+	sch.Advance(time.Hour)
+	require.Error(t, <-done)
+	require.Equal(t, int64(1), office.Count(actorkit.RetriesExhaustedReason))
+}
@@ -1,17 +1,24 @@
 package actorkit
 
-import "log"
+import (
+	"log"
+	"time"
+
+	"github.com/gokit/errors"
+)
 
 // AddRoute defines a giving message delivered
 // for adding sending address into route list.
 //
-// Used by the RoundRobin, RandomRouter, HashedRouter and Broadcast Router.
+// Used by the RoundRobin, RandomRouter, HashedRouter, SmallestMailboxRouter,
+// ScatterGatherRouter, TailChoppingRouter and Broadcast Router.
 type AddRoute struct{}
 
 // RemoveRoute defines a giving message delivered for
 // removing sending address from route list.
 //
-// Used by the RoundRobin, RandomRouter, HashedRouter and Broadcast Router.
+// Used by the RoundRobin, RandomRouter, HashedRouter, SmallestMailboxRouter,
+// ScatterGatherRouter, TailChoppingRouter and Broadcast Router.
 type RemoveRoute struct{}
 
 //***********************************************************
@@ -276,3 +283,503 @@ func (rr *HashedRouter) Action(addr Addr, msg Envelope) {
 		log.Printf("[Hashed:Routing] Message %q data of type %T must implement Hashed interface: %#v", msg.Ref.String(), msg.Data, msg)
 	}
 }
+
+//***********************************************************
+// SmallestMailboxRouter
+//***********************************************************
+
+// SmallestMailboxRouter implements a router which delivers messages to the address
+// with the smallest mailbox total, where idle addresses with empty mailboxes are
+// preferred in the order they were added, and addresses whose state is not RUNNING
+// are skipped. Addresses without a local actor have no known mailbox total, hence
+// are only delivered to when no running local address exists.
+//
+// It stores address by their Addr.Addr() which means even if two Addr are referencing
+// same Actor, they will be respected, added and broadcasted to, as the Addr represents
+// a unique capability.
+type SmallestMailboxRouter struct {
+	addrs *ServiceSet
+}
+
+// NewSmallestMailboxRouter returns a new instance of a SmallestMailboxRouter using
+// provided address list if any to setup.
+func NewSmallestMailboxRouter(addrs ...Addr) *SmallestMailboxRouter {
+	var service ServiceSet
+	for _, addr := range addrs {
+		service.Add(addr)
+	}
+
+	return &SmallestMailboxRouter{
+		addrs: &service,
+	}
+}
+
+// Action implements the Behaviour interface.
+func (rr *SmallestMailboxRouter) Action(addr Addr, msg Envelope) {
+	switch msg.Data.(type) {
+	case AddRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.Add(msg.Sender)
+		}
+	case RemoveRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.RemoveAddr(msg.Sender)
+		}
+	default:
+		target, ok := rr.smallest()
+		if !ok {
+			log.Printf("[SmallestMailbox:Routing] No running addr to route message %q", msg.Ref.String())
+			return
+		}
+
+		if err := target.Forward(msg); err != nil {
+			log.Printf("[SmallestMailbox:Routing] Failed to route message %q to addr %q: %#v", msg.Ref.String(), target.Addr(), err)
+		}
+	}
+}
+
+// smallest returns the running address with the smallest mailbox total.
+func (rr *SmallestMailboxRouter) smallest() (Addr, bool) {
+	var target, remote Addr
+	var smallest int
+
+	rr.addrs.ForEach(func(routee Addr, _ int) bool {
+		if routee.State() != RUNNING {
+			return true
+		}
+
+		actor := routee.Actor()
+		if actor == nil {
+			if remote == nil {
+				remote = routee
+			}
+			return true
+		}
+
+		total := actor.Mailbox().Total()
+		if target == nil || total < smallest {
+			target, smallest = routee, total
+		}
+
+		// an idle address can not be bettered.
+		return smallest > 0
+	})
+
+	if target != nil {
+		return target, true
+	}
+	return remote, remote != nil
+}
+
+//***********************************************************
+// PoolRouter
+//***********************************************************
+
+// PoolConfig defines configuration values which will be used
+// by PoolRouter for it's operations.
+type PoolConfig struct {
+	// Prop sets the Prop used to spawn routees.
+	//
+	// Prop.Supervisor defaults to a RestartingSupervisor, restarting
+	// routees which fail or panic.
+	Prop Prop
+
+	// Service sets the service name of spawned routees.
+	//
+	// Defaults to "routee".
+	Service string
+
+	// Size sets the initial total of routees.
+	//
+	// Defaults to Min if set, else 1.
+	Size int
+
+	// Min sets the minimum total of routees the pool is resized down to.
+	//
+	// Defaults to Size.
+	Min int
+
+	// Max sets the maximum total of routees the pool is resized up to.
+	// Resizing is disabled if Max is not above Min.
+	Max int
+
+	// Pressure sets the average mailbox total per routee, at or above
+	// which the pool is resized up by a routee. The pool is resized down
+	// by a routee when all routees have empty mailboxes.
+	//
+	// Defaults to 4.
+	Pressure int
+
+	// ResizeEvery sets the total of routed messages between resize checks.
+	//
+	// Defaults to 10.
+	ResizeEvery int
+}
+
+func (pc *PoolConfig) init() {
+	if pc.Service == "" {
+		pc.Service = "routee"
+	}
+
+	if pc.Prop.Supervisor == nil {
+		pc.Prop.Supervisor = &RestartingSupervisor{}
+	}
+
+	if pc.Size <= 0 {
+		pc.Size = pc.Min
+	}
+
+	if pc.Size <= 0 {
+		pc.Size = 1
+	}
+
+	if pc.Min <= 0 || pc.Min > pc.Size {
+		pc.Min = pc.Size
+	}
+
+	if pc.Max < pc.Size {
+		pc.Max = pc.Size
+	}
+
+	if pc.Pressure <= 0 {
+		pc.Pressure = 4
+	}
+
+	if pc.ResizeEvery <= 0 {
+		pc.ResizeEvery = 10
+	}
+}
+
+// PoolRouter implements a router which spawns it's own routees as children from
+// a Prop when started, delivering messages to them in a round robin manner.
+//
+// Routees are death watched, where routees which are stopped, killed or destroyed
+// are destroyed, removing them from the children of the router's actor, and replaced
+// by new routees while the router's actor is running. Routees are supervised by the
+// supervisor of PoolConfig.Prop, which restarts them by default.
+//
+// If Max is above Min, the pool is resized between both based on the mailbox totals
+// of routees, checked every ResizeEvery routed messages.
+//
+// AddRoute and RemoveRoute are ignored, as the pool manages it's own routees.
+type PoolRouter struct {
+	config PoolConfig
+	addrs  *ServiceSet
+	size   AtomicCounter
+	next   int
+	routed int
+}
+
+// NewPoolRouter returns a new instance of a PoolRouter.
+func NewPoolRouter(config PoolConfig) *PoolRouter {
+	config.init()
+
+	return &PoolRouter{
+		config: config,
+		addrs:  NewServiceSet(),
+	}
+}
+
+// Size returns the current total of routees within pool.
+func (rr *PoolRouter) Size() int {
+	return int(rr.size.Get())
+}
+
+// PreStart implements the PreStart interface, spawning the initial routees
+// of pool.
+func (rr *PoolRouter) PreStart(addr Addr) error {
+	for i := 0; i < rr.config.Size; i++ {
+		if err := rr.spawn(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Action implements the Behaviour interface.
+func (rr *PoolRouter) Action(addr Addr, msg Envelope) {
+	switch data := msg.Data.(type) {
+	case AddRoute, RemoveRoute:
+		return
+	case Terminated:
+		rr.terminated(addr, data)
+	default:
+		rr.routed++
+		if rr.config.Max > rr.config.Min && rr.routed%rr.config.ResizeEvery == 0 {
+			rr.resize(addr)
+		}
+
+		routees := rr.addrs.Set()
+		if len(routees) == 0 {
+			log.Printf("[Pool:Routing] No routee to route message %q", msg.Ref.String())
+			return
+		}
+
+		rr.next = (rr.next + 1) % len(routees)
+		target := routees[rr.next]
+		if err := target.Forward(msg); err != nil {
+			log.Printf("[Pool:Routing] Failed to route message %q to addr %q: %#v", msg.Ref.String(), target.Addr(), err)
+		}
+	}
+}
+
+func (rr *PoolRouter) terminated(addr Addr, data Terminated) {
+	if data.Addr == nil {
+		return
+	}
+
+	// routees removed by a resize are no longer known.
+	routee := data.Addr
+	if !rr.addrs.RemoveAddr(routee) {
+		return
+	}
+	rr.size.Set(int64(len(rr.addrs.Set())))
+
+	if actor := routee.Actor(); actor != nil {
+		if err := actor.Destroy(); err != nil {
+			log.Printf("[Pool:Routing] Failed to destroy terminated routee %q: %#v", routee.Addr(), err)
+		}
+	}
+
+	// router's actor may be shutting down, which terminates routees.
+	if addr.State() != RUNNING {
+		return
+	}
+
+	if err := rr.spawn(addr); err != nil {
+		log.Printf("[Pool:Routing] Failed to replace terminated routee %q: %#v", routee.Addr(), err)
+	}
+}
+
+func (rr *PoolRouter) resize(addr Addr) {
+	routees := rr.addrs.Set()
+
+	var total int
+	for _, routee := range routees {
+		if actor := routee.Actor(); actor != nil {
+			total += actor.Mailbox().Total()
+		}
+	}
+
+	if len(routees) < rr.config.Max && total >= rr.config.Pressure*len(routees) {
+		if err := rr.spawn(addr); err != nil {
+			log.Printf("[Pool:Routing] Failed to resize up pool: %#v", err)
+		}
+		return
+	}
+
+	if len(routees) > rr.config.Min && total == 0 {
+		routee := routees[len(routees)-1]
+		rr.addrs.RemoveAddr(routee)
+		rr.size.Set(int64(len(rr.addrs.Set())))
+
+		if err := routee.Actor().Destroy(); err != nil {
+			log.Printf("[Pool:Routing] Failed to destroy routee %q while resizing down pool: %#v", routee.Addr(), err)
+		}
+	}
+}
+
+func (rr *PoolRouter) spawn(addr Addr) error {
+	switch addr.State() {
+	case STARTING, RUNNING:
+	default:
+		return errors.WrapOnly(ErrActorMustBeRunning)
+	}
+
+	routee, err := addr.Spawn(rr.config.Service, rr.config.Prop)
+	if err != nil {
+		return err
+	}
+
+	if err := addr.DeathWatch(routee); err != nil {
+		return err
+	}
+
+	rr.addrs.Add(routee)
+	rr.size.Set(int64(len(rr.addrs.Set())))
+	return nil
+}
+
+//***********************************************************
+// ScatterGatherRouter
+//***********************************************************
+
+// ScatterGatherRouter implements a router which delivers every request to all
+// addresses at once, resolving the Future which sent the request with the first
+// successful reply, whereupon all other requests are cancelled. If all addresses
+// fail or time out, the sender's Future is rejected with the last error.
+//
+// Replies for senders which are not a Future are forwarded to the sender. Requests
+// carry the context of the routed envelope, and are cancelled once it's done.
+//
+// It stores address by their Addr.Addr() which means even if two Addr are referencing
+// same Actor, they will be respected, added and broadcasted to, as the Addr represents
+// a unique capability.
+type ScatterGatherRouter struct {
+	addrs   *ServiceSet
+	timeout time.Duration
+}
+
+// NewScatterGatherRouter returns a new instance of a ScatterGatherRouter using
+// provided timeout for each request and address list if any to setup. A zero
+// timeout disables timing out of requests, which then end when the Future which
+// sent them is resolved or rejected.
+func NewScatterGatherRouter(timeout time.Duration, addrs ...Addr) *ScatterGatherRouter {
+	var service ServiceSet
+	for _, addr := range addrs {
+		service.Add(addr)
+	}
+
+	return &ScatterGatherRouter{
+		addrs:   &service,
+		timeout: timeout,
+	}
+}
+
+// Action implements the Behaviour interface.
+func (rr *ScatterGatherRouter) Action(addr Addr, msg Envelope) {
+	switch msg.Data.(type) {
+	case AddRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.Add(msg.Sender)
+		}
+	case RemoveRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.RemoveAddr(msg.Sender)
+		}
+	default:
+		result := NewFuture(addr)
+
+		routees := rr.addrs.Set()
+		if len(routees) == 0 {
+			result.Escalate(ErrHedgeNoAddr)
+		} else {
+			scatterTo(result, msg, rr.timeout, routees)
+		}
+
+		cancelRouted(msg, result)
+
+		go replyRouted("ScatterGather", msg, result)
+	}
+}
+
+//***********************************************************
+// TailChoppingRouter
+//***********************************************************
+
+// TailChoppingRouter implements a router which delivers every request to an
+// address, and if no reply arrives within interval or the request fails, delivers
+// the same request to the next address, till one replies, all addresses are
+// exhausted or the deadline elapses. The Future which sent the request is resolved
+// with the first successful reply, else rejected with the last error or
+// ErrFutureTimeout if the deadline elapsed, whereupon all pending requests are
+// cancelled.
+//
+// Requests are delivered to addresses in a round robin manner, starting from the
+// address after the one a previous request started from.
+//
+// Replies for senders which are not a Future are forwarded to the sender. Requests
+// carry the context of the routed envelope, where no further request is delivered
+// once it's done.
+//
+// It stores address by their Addr.Addr() which means even if two Addr are referencing
+// same Actor, they will be respected, added and broadcasted to, as the Addr represents
+// a unique capability.
+type TailChoppingRouter struct {
+	addrs    *ServiceSet
+	interval time.Duration
+	deadline time.Duration
+	next     int
+}
+
+// NewTailChoppingRouter returns a new instance of a TailChoppingRouter using
+// provided interval between requests, deadline of all requests and address list
+// if any to setup. A zero deadline disables timing out of requests, which then
+// end when the Future which sent them is resolved or rejected.
+func NewTailChoppingRouter(interval time.Duration, deadline time.Duration, addrs ...Addr) *TailChoppingRouter {
+	var service ServiceSet
+	for _, addr := range addrs {
+		service.Add(addr)
+	}
+
+	return &TailChoppingRouter{
+		addrs:    &service,
+		interval: interval,
+		deadline: deadline,
+	}
+}
+
+// Action implements the Behaviour interface.
+func (rr *TailChoppingRouter) Action(addr Addr, msg Envelope) {
+	switch msg.Data.(type) {
+	case AddRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.Add(msg.Sender)
+		}
+	case RemoveRoute:
+		if msg.Sender != nil && msg.Sender.ID() != addr.ID() {
+			rr.addrs.RemoveAddr(msg.Sender)
+		}
+	default:
+		result := requestFuture(addr, rr.deadline)
+
+		routees := rr.addrs.Set()
+		if len(routees) == 0 {
+			result.Escalate(ErrHedgeNoAddr)
+		} else {
+			start := rr.next % len(routees)
+			rr.next = start + 1
+
+			ordered := make([]Addr, 0, len(routees))
+			ordered = append(ordered, routees[start:]...)
+			ordered = append(ordered, routees[:start]...)
+			hedgeTo(result, msg, rr.interval, rr.deadline, ordered)
+		}
+
+		cancelRouted(msg, result)
+
+		go replyRouted("TailChopping", msg, result)
+	}
+}
+
+// cancelRouted rejects the result of a routed request with ErrHedgeCancelled
+// once the Future which sent the request is resolved or rejected, such as when
+// it times out, which ends all pending requests.
+func cancelRouted(msg Envelope, result *FutureImpl) {
+	if future, ok := msg.Sender.(Future); ok {
+		future.PipeAction(func(Envelope) {
+			result.Escalate(ErrHedgeCancelled)
+		})
+	}
+}
+
+// replyRouted waits for the result of a routed request, resolving or rejecting
+// the Future which sent the request, else forwarding a reply to the sender.
+func replyRouted(router string, msg Envelope, result *FutureImpl) {
+	err := result.Wait()
+
+	if future, ok := msg.Sender.(Future); ok {
+		if err != nil {
+			future.Escalate(err)
+			return
+		}
+
+		if ferr := future.Forward(result.Result()); ferr != nil {
+			log.Printf("[%s:Routing] Failed to deliver reply of message %q: %#v", router, msg.Ref.String(), ferr)
+		}
+		return
+	}
+
+	if err != nil {
+		log.Printf("[%s:Routing] Message %q failed: %#v", router, msg.Ref.String(), err)
+		return
+	}
+
+	if msg.Sender == nil {
+		return
+	}
+
+	if ferr := msg.Sender.Forward(result.Result()); ferr != nil {
+		log.Printf("[%s:Routing] Failed to deliver reply of message %q to addr %q: %#v", router, msg.Ref.String(), msg.Sender.Addr(), ferr)
+	}
+}
@@ -0,0 +1,407 @@
+package actorkit_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func waitFor(t *testing.T, cond func() bool) {
+	for i := 0; i < 200 && !cond(); i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.True(t, cond())
+}
+
+func TestPoolRouterReplacesTerminatedRoutees(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	received := make(chan *actorkit.Envelope, 10)
+	pool := actorkit.NewPoolRouter(actorkit.PoolConfig{
+		Size: 3,
+		Prop: actorkit.Prop{Behaviour: &basic{Message: received}},
+	})
+
+	router, err := system.Spawn("pool", actorkit.Prop{Behaviour: pool})
+	require.NoError(t, err)
+	require.Equal(t, 3, pool.Size())
+
+	routees := router.Children()
+	require.Len(t, routees, 3)
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, router.Send(i, nil))
+	}
+	for i := 0; i < 6; i++ {
+		<-received
+	}
+
+	killed := routees[0]
+	require.NoError(t, killed.Actor().Kill())
+
+	waitFor(t, func() bool {
+		children := router.Children()
+		for _, child := range children {
+			if child.ID() == killed.ID() || child.State() != actorkit.RUNNING {
+				return false
+			}
+		}
+		return len(children) == 3
+	})
+	require.Equal(t, 3, pool.Size())
+	require.Equal(t, actorkit.DESTROYED, killed.State())
+
+	require.NoError(t, router.Send("after", nil))
+	require.Equal(t, "after", (<-received).Data)
+}
+
+func TestPoolRouterResizes(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	release := make(chan struct{})
+	pool := actorkit.NewPoolRouter(actorkit.PoolConfig{
+		Min:         1,
+		Max:         3,
+		Pressure:    1,
+		ResizeEvery: 1,
+		Prop: actorkit.Prop{
+			Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+				<-release
+			}),
+		},
+	})
+
+	router, err := system.Spawn("pool", actorkit.Prop{Behaviour: pool})
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.Size())
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, router.Send(i, nil))
+	}
+
+	waitFor(t, func() bool {
+		return pool.Size() == 3
+	})
+
+	close(release)
+
+	waitFor(t, func() bool {
+		router.Send("tick", nil)
+		return pool.Size() == 1 && len(router.Children()) == 1
+	})
+}
+
+func TestSmallestMailboxRouter(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	release := make(chan struct{})
+	busy, err := system.Spawn("busy", actorkit.Prop{
+		Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+			<-release
+		}),
+	})
+	require.NoError(t, err)
+	defer close(release)
+
+	stopped, err := system.Spawn("stopped", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+	require.NoError(t, stopped.Actor().Kill())
+	waitFor(t, func() bool {
+		return stopped.State() != actorkit.RUNNING
+	})
+
+	received := make(chan *actorkit.Envelope, 10)
+	idle, err := system.Spawn("idle", actorkit.Prop{Behaviour: &basic{Message: received}})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, busy.Send(i, nil))
+	}
+	waitFor(t, func() bool {
+		return busy.Actor().Mailbox().Total() == 2
+	})
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewSmallestMailboxRouter(busy, stopped),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, router.Send(actorkit.AddRoute{}, idle))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, router.Send(i, nil))
+		require.Equal(t, i, (<-received).Data)
+	}
+
+	require.NoError(t, router.Send(actorkit.RemoveRoute{}, idle))
+	require.NoError(t, router.Send("busy", nil))
+
+	waitFor(t, func() bool {
+		return busy.Actor().Mailbox().Total() == 3
+	})
+
+	select {
+	case env := <-received:
+		t.Fatalf("unexpected delivery to removed routee: %#v", env)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScatterGatherRouter(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	slowReplies := make(chan error, 1)
+	slow := replier(t, "slow", 100*time.Millisecond, slowReplies)
+	fast := replier(t, "fast", 0, nil)
+	defer actorkit.Destroy(slow)
+	defer actorkit.Destroy(fast)
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewScatterGatherRouter(time.Second, slow, fast),
+	})
+	require.NoError(t, err)
+
+	future := router.Future()
+	require.NoError(t, router.Send("ping", future))
+	require.NoError(t, future.Wait())
+	require.Equal(t, "fast", future.Result().Data)
+
+	// late reply of cancelled request is rejected.
+	require.Error(t, <-slowReplies)
+
+	require.NoError(t, router.Send(actorkit.RemoveRoute{}, slow))
+	require.NoError(t, router.Send(actorkit.RemoveRoute{}, fast))
+
+	empty := router.Future()
+	require.NoError(t, router.Send("ping", empty))
+	require.Error(t, empty.Wait())
+}
+
+func TestTailChoppingRouter(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	slow := replier(t, "slow", 100*time.Millisecond, nil)
+	fast := replier(t, "fast", 0, nil)
+	defer actorkit.Destroy(slow)
+	defer actorkit.Destroy(fast)
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewTailChoppingRouter(20*time.Millisecond, time.Second, slow, fast),
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	future := router.Future()
+	require.NoError(t, router.Send("ping", future))
+	require.NoError(t, future.Wait())
+	require.Equal(t, "fast", future.Result().Data)
+	require.True(t, time.Since(start) < 100*time.Millisecond)
+}
+
+func TestTailChoppingRouterDeadline(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	firstReplies := make(chan error, 1)
+	secondReplies := make(chan error, 1)
+	first := replier(t, "first", 100*time.Millisecond, firstReplies)
+	second := replier(t, "second", 100*time.Millisecond, secondReplies)
+	defer actorkit.Destroy(first)
+	defer actorkit.Destroy(second)
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewTailChoppingRouter(10*time.Millisecond, 50*time.Millisecond, first, second),
+	})
+	require.NoError(t, err)
+
+	future := router.Future()
+	require.NoError(t, router.Send("ping", future))
+
+	err = future.Wait()
+	require.Error(t, err)
+	require.Equal(t, actorkit.ErrFutureTimeout.Error(), err.Error())
+
+	// replies after the deadline are rejected.
+	require.Error(t, <-firstReplies)
+	require.Error(t, <-secondReplies)
+}
+
+func TestScatterGatherRouterSendsToAllRoutees(t *testing.T) {
+	sch := testkit.NewScheduler(1)
+	system, err := testkit.NewSystem(sch.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	received := make(chan string, 3)
+	var routees []actorkit.Addr
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		routee, err := system.Spawn(name, actorkit.Prop{
+			Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+				received <- name
+				env.Sender.Send(name, addr)
+			}),
+		})
+		require.NoError(t, err)
+		routees = append(routees, routee)
+	}
+
+	// a zero timeout never times out requests.
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewScatterGatherRouter(0, routees...),
+	})
+	require.NoError(t, err)
+
+	future := router.Future()
+	require.NoError(t, router.Send("ping", future))
+
+	// routees may not await delivery yet when a run ends.
+	for i := 0; i < 200 && (i == 0 || sch.Pending() > 0); i++ {
+		_, err = sch.Run()
+		require.NoError(t, err)
+		runtime.Gosched()
+	}
+	require.NoError(t, future.Wait())
+	require.Contains(t, []interface{}{"a", "b", "c"}, future.Result().Data)
+	require.Len(t, received, 3)
+}
+
+func TestTailChoppingRouterZeroDeadline(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	slow := replier(t, "slow", 50*time.Millisecond, nil)
+	defer actorkit.Destroy(slow)
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewTailChoppingRouter(10*time.Millisecond, 0, slow),
+	})
+	require.NoError(t, err)
+
+	future := router.Future()
+	require.NoError(t, router.Send("ping", future))
+	require.NoError(t, future.Wait())
+	require.Equal(t, "slow", future.Result().Data)
+}
+
+func TestPoolRouterRestartsPanickingRoutees(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	received := make(chan interface{}, 10)
+	pool := actorkit.NewPoolRouter(actorkit.PoolConfig{
+		Prop: actorkit.Prop{
+			Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+				if env.Data == "boom" {
+					panic("routee failed")
+				}
+				received <- env.Data
+			}),
+		},
+	})
+
+	router, err := system.Spawn("pool", actorkit.Prop{Behaviour: pool})
+	require.NoError(t, err)
+
+	routees := router.Children()
+	require.Len(t, routees, 1)
+
+	require.NoError(t, router.Send("boom", nil))
+	waitFor(t, func() bool {
+		return routees[0].Actor().Stats().Restarted == 1 && isRunning(routees[0])
+	})
+
+	require.NoError(t, router.Send("after", nil))
+	require.Equal(t, "after", <-received)
+	require.Equal(t, 1, pool.Size())
+	require.Equal(t, routees[0].ID(), router.Children()[0].ID())
+}
+
+func TestTailChoppingRouterCarriesContext(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	first := make(chan actorkit.Envelope, 1)
+	second := make(chan actorkit.Envelope, 1)
+	silent := func(name string, received chan actorkit.Envelope) actorkit.Addr {
+		addr, err := system.Spawn(name, actorkit.Prop{
+			Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+				received <- env
+			}),
+		})
+		require.NoError(t, err)
+		return addr
+	}
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewTailChoppingRouter(time.Second, 0, silent("first", first), silent("second", second)),
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	future := router.Future()
+	require.NoError(t, router.Forward(actorkit.CreateEnvelope(future, actorkit.Header{}, "ping").WithContext(ctx)))
+
+	request := <-first
+	require.NoError(t, request.Context().Err())
+
+	cancel()
+	require.Error(t, future.Wait())
+	require.Error(t, request.Context().Err())
+	require.Len(t, second, 0)
+}
+
+func TestScatterGatherRouterEndsWithSender(t *testing.T) {
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	received := make(chan actorkit.Envelope, 1)
+	silent, err := system.Spawn("silent", actorkit.Prop{
+		Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+			received <- env
+		}),
+	})
+	require.NoError(t, err)
+
+	router, err := system.Spawn("router", actorkit.Prop{
+		Behaviour: actorkit.NewScatterGatherRouter(0, silent),
+	})
+	require.NoError(t, err)
+
+	future := actorkit.TimedFuture(router, 20*time.Millisecond)
+	require.NoError(t, router.Send("ping", future))
+	require.Error(t, future.Wait())
+
+	request := <-received
+	ended := make(chan error, 1)
+	go func() {
+		ended <- request.Sender.(actorkit.Future).Wait()
+	}()
+
+	select {
+	case err := <-ended:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("request outlived the Future which sent it")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/gokit/actorkit/retries"
 )
 
 // Decider defines a function which giving a value will return a directive.
@@ -15,6 +17,142 @@ type Decider func(interface{}) Directive
 // increasing attempts, will return an appropriate duration.
 type DelayProvider func(int) time.Duration
 
+//*****************************************************************
+// RestartIntensity
+//*****************************************************************
+
+// RestartIntensity defines the maximum restarts allowed for an actor within a
+// sliding window of time, after which the Exceeded directive is applied instead
+// of a restart. Restarts older than the window age out, ensuring only actors
+// which fail repeatedly within a short period are given up on.
+//
+// Restarts are tracked per target actor, hence a single RestartIntensity can be
+// shared by a supervisor across all it's supervised actors, but should not be
+// shared across supervisors.
+type RestartIntensity struct {
+	// MaxRestarts sets the maximum restarts allowed within the window. Restarts
+	// are unlimited if MaxRestarts is not above zero.
+	MaxRestarts int
+
+	// Within sets the duration of the window.
+	Within time.Duration
+
+	// Exceeded sets the directive applied once restarts are exceeded, where
+	// IgnoreDirective and RestartDirective are treated as StopDirective.
+	Exceeded Directive
+
+	ml       sync.Mutex
+	restarts map[string][]time.Time
+}
+
+// NewRestartIntensity returns a new RestartIntensity allowing giving maximum restarts
+// within provided window, after which provided directive is applied.
+func NewRestartIntensity(max int, within time.Duration, exceeded Directive) *RestartIntensity {
+	return &RestartIntensity{MaxRestarts: max, Within: within, Exceeded: exceeded}
+}
+
+// Allow records a restart of provided actor at the time of it's clock, returning
+// false if the restart exceeds the intensity, in which case all recorded restarts
+// of the actor are cleared.
+//
+// A nil RestartIntensity allows all restarts.
+func (ri *RestartIntensity) Allow(target Actor) bool {
+	if ri == nil || ri.MaxRestarts <= 0 {
+		return true
+	}
+
+	now := target.Clock().Now()
+	start := now.Add(-ri.Within)
+
+	ri.ml.Lock()
+	defer ri.ml.Unlock()
+
+	if ri.restarts == nil {
+		ri.restarts = map[string][]time.Time{}
+	}
+
+	id := target.ID()
+	recent := ri.restarts[id][:0]
+	for _, restart := range ri.restarts[id] {
+		if restart.After(start) {
+			recent = append(recent, restart)
+		}
+	}
+
+	if len(recent) >= ri.MaxRestarts {
+		delete(ri.restarts, id)
+		return false
+	}
+
+	ri.restarts[id] = append(recent, now)
+	return true
+}
+
+// Restarts returns the total restarts of provided actor within the current window.
+func (ri *RestartIntensity) Restarts(target Actor) int {
+	if ri == nil {
+		return 0
+	}
+
+	start := target.Clock().Now().Add(-ri.Within)
+
+	ri.ml.Lock()
+	defer ri.ml.Unlock()
+
+	var total int
+	for _, restart := range ri.restarts[target.ID()] {
+		if restart.After(start) {
+			total++
+		}
+	}
+	return total
+}
+
+// Directive returns the directive to be applied once restarts are exceeded.
+func (ri *RestartIntensity) Directive() Directive {
+	switch ri.Exceeded {
+	case IgnoreDirective, RestartDirective:
+		return StopDirective
+	}
+	return ri.Exceeded
+}
+
+// applyExceeded applies provided directive which is not a RestartDirective to target
+// actor, in a one-for-one manner.
+func applyExceeded(directive Directive, invoker SupervisionInvoker, err interface{}, targetAddr Addr, target Actor, parent Actor) {
+	switch directive {
+	case PanicDirective:
+		linearDoUntil(target.Kill, 100, time.Second)
+		if invoker != nil {
+			invoker.InvokedKill(err, target.Stats(), targetAddr, target)
+		}
+
+		switch tm := err.(type) {
+		case PanicEvent:
+			panic(fmt.Sprintf("%v\n%s", tm.Panic, tm.Stack))
+		default:
+			panic(err)
+		}
+	case KillDirective:
+		linearDoUntil(target.Kill, 100, time.Second)
+		if invoker != nil {
+			invoker.InvokedKill(err, target.Stats(), targetAddr, target)
+		}
+	case StopDirective:
+		linearDoUntil(target.Stop, 100, time.Second)
+		if invoker != nil {
+			invoker.InvokedStop(err, target.Stats(), targetAddr, target)
+		}
+	case DestroyDirective:
+		linearDoUntil(target.Destroy, 100, time.Second)
+		if invoker != nil {
+			invoker.InvokedDestroy(err, target.Stats(), targetAddr, target)
+		}
+	case EscalateDirective:
+		parent.Escalate(err, targetAddr)
+	}
+}
+
 //*****************************************************************
 // AllForOneSupervisor
 //*****************************************************************
@@ -31,6 +169,10 @@ type AllForOneSupervisor struct {
 	Delay       DelayProvider
 	Invoker     SupervisionInvoker
 
+	// Intensity sets the restart intensity of supervised actors, where the
+	// exceeded directive is applied to all siblings.
+	Intensity *RestartIntensity
+
 	failedRestarts int64
 	work           sync.Mutex
 }
@@ -42,7 +184,11 @@ func (on *AllForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 	on.work.Lock()
 	defer on.work.Unlock()
 
-	switch on.Decider(err) {
+	on.handle(on.Decider(err), err, targetAddr, target, parent)
+}
+
+func (on *AllForOneSupervisor) handle(directive Directive, err interface{}, targetAddr Addr, target Actor, parent Actor) {
+	switch directive {
 	case PanicDirective:
 		linearDoUntil(parent.KillChildren, 100, time.Second)
 
@@ -57,7 +203,7 @@ func (on *AllForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 
 		switch tm := err.(type) {
 		case PanicEvent:
-			panic(fmt.Sprintf("%#q\n", tm))
+			panic(fmt.Sprintf("%v\n%s", tm.Panic, tm.Stack))
 		default:
 			panic(err)
 		}
@@ -77,6 +223,11 @@ func (on *AllForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 			return
 		}
 
+		if !on.Intensity.Allow(target) {
+			on.handle(on.Intensity.Directive(), err, targetAddr, target, parent)
+			return
+		}
+
 		restartErr := target.Restart()
 		if on.Invoker != nil {
 			on.Invoker.InvokedRestart(err, target.Stats(), targetAddr, target)
@@ -86,12 +237,12 @@ func (on *AllForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 			newFailed := atomic.AddInt64(&on.failedRestarts, 1)
 
 			if on.Delay != nil {
-				time.AfterFunc(on.Delay(int(newFailed)), func() {
+				target.Clock().AfterFunc(on.Delay(int(newFailed)), func() {
 					on.Handle(err, targetAddr, target, parent)
 				})
 				return
 			}
-			on.Handle(err, targetAddr, target, parent)
+			on.handle(directive, err, targetAddr, target, parent)
 			return
 		}
 
@@ -121,6 +272,9 @@ type OneForOneSupervisor struct {
 	PanicAction PanicAction
 	Invoker     SupervisionInvoker
 
+	// Intensity sets the restart intensity of supervised actors.
+	Intensity *RestartIntensity
+
 	failedRestarts int64
 	work           sync.Mutex
 }
@@ -132,7 +286,11 @@ func (on *OneForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 	on.work.Lock()
 	defer on.work.Unlock()
 
-	switch on.Decider(err) {
+	on.handle(on.Decider(err), err, targetAddr, target, parent)
+}
+
+func (on *OneForOneSupervisor) handle(directive Directive, err interface{}, targetAddr Addr, target Actor, parent Actor) {
+	switch directive {
 	case PanicDirective:
 		linearDoUntil(target.Kill, 100, time.Second)
 
@@ -147,7 +305,7 @@ func (on *OneForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 
 		switch tm := err.(type) {
 		case PanicEvent:
-			panic(fmt.Sprintf("%#q\n", tm))
+			panic(fmt.Sprintf("%v\n%s", tm.Panic, tm.Stack))
 		default:
 			panic(err)
 		}
@@ -167,6 +325,11 @@ func (on *OneForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 			return
 		}
 
+		if !on.Intensity.Allow(target) {
+			on.handle(on.Intensity.Directive(), err, targetAddr, target, parent)
+			return
+		}
+
 		restartErr := target.Restart()
 		if on.Invoker != nil {
 			on.Invoker.InvokedRestart(err, target.Stats(), targetAddr, target)
@@ -176,13 +339,13 @@ func (on *OneForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 			newFailed := atomic.AddInt64(&on.failedRestarts, 1)
 
 			if on.Delay != nil {
-				time.AfterFunc(on.Delay(int(newFailed)), func() {
+				target.Clock().AfterFunc(on.Delay(int(newFailed)), func() {
 					on.Handle(err, targetAddr, target, parent)
 				})
 				return
 			}
 
-			on.Handle(err, targetAddr, target, parent)
+			on.handle(directive, err, targetAddr, target, parent)
 			return
 		}
 
@@ -206,8 +369,13 @@ func (on *OneForOneSupervisor) Handle(err interface{}, targetAddr Addr, target A
 
 // RestartingSupervisor implements a one-to-one supervising strategy for giving actors.
 type RestartingSupervisor struct {
-	Delay    DelayProvider
-	Invoker  SupervisionInvoker
+	Delay   DelayProvider
+	Invoker SupervisionInvoker
+
+	// Intensity sets the restart intensity of supervised actors, without which
+	// actors are restarted unconditionally.
+	Intensity *RestartIntensity
+
 	work     sync.Mutex
 	attempts int
 }
@@ -218,6 +386,16 @@ func (sp *RestartingSupervisor) Handle(err interface{}, targetAddr Addr, target
 	sp.work.Lock()
 	defer sp.work.Unlock()
 
+	sp.handle(err, targetAddr, target, parent)
+}
+
+func (sp *RestartingSupervisor) handle(err interface{}, targetAddr Addr, target Actor, parent Actor) {
+	if !sp.Intensity.Allow(target) {
+		sp.attempts = 0
+		applyExceeded(sp.Intensity.Directive(), sp.Invoker, err, targetAddr, target, parent)
+		return
+	}
+
 	restartErr := target.Restart()
 	if sp.Invoker != nil {
 		sp.Invoker.InvokedRestart(err, target.Stats(), targetAddr, target)
@@ -230,12 +408,12 @@ func (sp *RestartingSupervisor) Handle(err interface{}, targetAddr Addr, target
 
 	sp.attempts++
 	if sp.Delay != nil {
-		time.AfterFunc(sp.Delay(sp.attempts), func() {
+		target.Clock().AfterFunc(sp.Delay(sp.attempts), func() {
 			sp.Handle(err, targetAddr, target, parent)
 		})
 		return
 	}
-	sp.Handle(err, targetAddr, target, parent)
+	sp.handle(err, targetAddr, target, parent)
 }
 
 //*****************************************************************
@@ -281,6 +459,11 @@ type ExponentialBackOffSupervisor struct {
 	Invoker SupervisionInvoker
 	Action  func(err interface{}, targetAddr Addr, target Actor, parent Actor) error
 
+	// Intensity sets the maximum runs of action for an actor within a window of
+	// time, which applies across failures unlike Max which only limits
+	// consecutive failed runs.
+	Intensity *RestartIntensity
+
 	failed int64
 	work   sync.Mutex
 }
@@ -300,6 +483,12 @@ func (sp *ExponentialBackOffSupervisor) Handle(err interface{}, targetAddr Addr,
 		return
 	}
 
+	if !sp.Intensity.Allow(target) {
+		atomic.StoreInt64(&sp.failed, 0)
+		applyExceeded(sp.Intensity.Directive(), sp.Invoker, err, targetAddr, target, parent)
+		return
+	}
+
 	var backoff int64
 	if failed > 0 {
 		backoff = failed * sp.Backoff.Nanoseconds()
@@ -310,7 +499,7 @@ func (sp *ExponentialBackOffSupervisor) Handle(err interface{}, targetAddr Addr,
 	noise := rand.Int63n(500)
 	dur := time.Duration(backoff + noise)
 
-	time.AfterFunc(dur, func() {
+	target.Clock().AfterFunc(dur, func() {
 		actionErr := sp.Action(err, targetAddr, target, parent)
 		if sp.Invoker != nil {
 			sp.Invoker.InvokedRestart(err, target.Stats(), targetAddr, target)
@@ -325,3 +514,141 @@ func (sp *ExponentialBackOffSupervisor) Handle(err interface{}, targetAddr Addr,
 		atomic.StoreInt64(&sp.failed, 0)
 	})
 }
+
+//*****************************************************************
+// BackoffSupervisor
+//*****************************************************************
+
+const defaultStashLimit = 1000
+
+// backoffState holds the restart attempts of an actor supervised by a
+// BackoffSupervisor.
+type backoffState struct {
+	attempts  int
+	restarted time.Time
+}
+
+// BackoffSupervisor implements a supervisor which restarts a failed actor after a
+// jittered backoff delay increasing with each consecutive failure.
+//
+// The actor is suspended during the delay if it implements the Suspendable
+// interface, which keeps it's address valid by stashing received messages, which
+// are replayed once it runs again, else it is stopped during the delay.
+//
+// The backoff is reset once an actor runs for ResetAfter since it's last restart
+// before failing again.
+type BackoffSupervisor struct {
+	// Backoff returns the delay before a restart for giving attempt, starting from
+	// zero. Defaults to retries.ExponentialJitterBackOff.
+	Backoff DelayProvider
+
+	// MaxBackoff sets the maximum delay before a restart, if above zero.
+	MaxBackoff time.Duration
+
+	// ResetAfter sets the duration an actor must run after a restart for it's
+	// backoff to be reset, if above zero.
+	ResetAfter time.Duration
+
+	// StashLimit sets the maximum messages stashed for a suspended actor, after
+	// which messages are dead-lettered. Defaults to 1000.
+	StashLimit int
+
+	// Intensity sets the restart intensity of supervised actors.
+	Intensity *RestartIntensity
+
+	Invoker SupervisionInvoker
+
+	work   sync.Mutex
+	states map[string]*backoffState
+}
+
+// NewBackoffSupervisor returns a new BackoffSupervisor restarting actors with
+// jittered exponential delays capped at provided maximum, which are reset after
+// provided period of stability.
+func NewBackoffSupervisor(max time.Duration, resetAfter time.Duration, invoker SupervisionInvoker) *BackoffSupervisor {
+	return &BackoffSupervisor{
+		MaxBackoff: max,
+		ResetAfter: resetAfter,
+		Invoker:    invoker,
+	}
+}
+
+// Handle implements the Supervisor interface, suspending target actor and
+// scheduling it's restart after it's current backoff delay.
+func (sp *BackoffSupervisor) Handle(err interface{}, targetAddr Addr, target Actor, parent Actor) {
+	sp.work.Lock()
+	defer sp.work.Unlock()
+
+	if sp.states == nil {
+		sp.states = map[string]*backoffState{}
+	}
+
+	state, ok := sp.states[target.ID()]
+	if !ok {
+		state = &backoffState{}
+		sp.states[target.ID()] = state
+	}
+
+	now := target.Clock().Now()
+	if sp.ResetAfter > 0 && !state.restarted.IsZero() && now.Sub(state.restarted) >= sp.ResetAfter {
+		state.attempts = 0
+	}
+
+	if !sp.Intensity.Allow(target) {
+		delete(sp.states, target.ID())
+		applyExceeded(sp.Intensity.Directive(), sp.Invoker, err, targetAddr, target, parent)
+		return
+	}
+
+	delay := sp.delay(state.attempts)
+	state.attempts++
+
+	if suspendable, ok := target.(Suspendable); ok {
+		limit := sp.StashLimit
+		if limit <= 0 {
+			limit = defaultStashLimit
+		}
+		suspendable.Suspend(limit)
+	} else {
+		target.Stop()
+	}
+
+	target.Clock().AfterFunc(delay, func() {
+		sp.work.Lock()
+		state.restarted = target.Clock().Now()
+		sp.work.Unlock()
+
+		restartErr := target.Restart()
+		if sp.Invoker != nil {
+			sp.Invoker.InvokedRestart(err, target.Stats(), targetAddr, target)
+		}
+
+		if restartErr != nil {
+			sp.Handle(err, targetAddr, target, parent)
+		}
+	})
+}
+
+// Attempts returns the current consecutive restart attempts of provided actor.
+func (sp *BackoffSupervisor) Attempts(target Actor) int {
+	sp.work.Lock()
+	defer sp.work.Unlock()
+
+	if state, ok := sp.states[target.ID()]; ok {
+		return state.attempts
+	}
+	return 0
+}
+
+func (sp *BackoffSupervisor) delay(attempt int) time.Duration {
+	backoff := sp.Backoff
+	if backoff == nil {
+		backoff = retries.ExponentialJitterBackOff
+	}
+
+	delay := backoff(attempt)
+	if sp.MaxBackoff > 0 && delay > sp.MaxBackoff {
+		delay = sp.MaxBackoff
+	}
+	return delay
+}
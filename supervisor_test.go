@@ -476,3 +476,186 @@ func TestAllForOneSupervisor(t *testing.T) {
 		child2.Actor().Destroy()
 	}
 }
+
+type manualClock struct {
+	ml  sync.Mutex
+	now time.Time
+}
+
+func (m *manualClock) Now() time.Time {
+	m.ml.Lock()
+	defer m.ml.Unlock()
+	return m.now
+}
+
+func (m *manualClock) Advance(d time.Duration) {
+	m.ml.Lock()
+	m.now = m.now.Add(d)
+	m.ml.Unlock()
+}
+
+func (m *manualClock) AfterFunc(d time.Duration, fn func()) actorkit.Timer {
+	return time.AfterFunc(d, fn)
+}
+
+func TestRestartIntensity(t *testing.T) {
+	clock := &manualClock{now: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{Clock: clock})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	child, err := system.Spawn("basic", actorkit.Prop{Behaviour: &basic{}})
+	require.NoError(t, err)
+
+	intensity := actorkit.NewRestartIntensity(2, time.Minute, actorkit.IgnoreDirective)
+	require.Equal(t, actorkit.StopDirective, intensity.Directive())
+
+	require.True(t, intensity.Allow(child.Actor()))
+	clock.Advance(30 * time.Second)
+	require.True(t, intensity.Allow(child.Actor()))
+	require.Equal(t, 2, intensity.Restarts(child.Actor()))
+
+	// first restart ages out of the window.
+	clock.Advance(31 * time.Second)
+	require.Equal(t, 1, intensity.Restarts(child.Actor()))
+	require.True(t, intensity.Allow(child.Actor()))
+
+	require.False(t, intensity.Allow(child.Actor()))
+	require.Equal(t, 0, intensity.Restarts(child.Actor()))
+
+	var unlimited *actorkit.RestartIntensity
+	require.True(t, unlimited.Allow(child.Actor()))
+}
+
+func TestSupervisorsRespectRestartIntensity(t *testing.T) {
+	clock := &manualClock{now: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{Clock: clock})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	supervisors := map[string]actorkit.Supervisor{
+		"one-for-one": &actorkit.OneForOneSupervisor{
+			Decider: func(_ interface{}) actorkit.Directive {
+				return actorkit.RestartDirective
+			},
+			Intensity: actorkit.NewRestartIntensity(2, time.Minute, actorkit.StopDirective),
+		},
+		"restarting": &actorkit.RestartingSupervisor{
+			Intensity: actorkit.NewRestartIntensity(2, time.Minute, actorkit.StopDirective),
+		},
+	}
+
+	for name, supervisor := range supervisors {
+		t.Run(name, func(t *testing.T) {
+			child, err := system.Spawn("basic", actorkit.Prop{Behaviour: &basic{}})
+			require.NoError(t, err)
+
+			supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+			supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+			require.True(t, isRunning(child))
+			require.Equal(t, int64(2), child.Actor().Stats().Restarted)
+
+			supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+			require.Equal(t, actorkit.STOPPED, child.Actor().State())
+			require.Equal(t, int64(2), child.Actor().Stats().Restarted)
+		})
+	}
+}
+
+func TestBackoffSupervisorStashesMessages(t *testing.T) {
+	clock := &manualClock{now: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)}
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{Clock: clock})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	base := &basic{Message: make(chan *actorkit.Envelope, 3)}
+	child, err := system.Spawn("basic", actorkit.Prop{Behaviour: base})
+	require.NoError(t, err)
+
+	supervisor := &actorkit.BackoffSupervisor{
+		Backoff: func(attempt int) time.Duration {
+			return time.Duration(attempt+1) * 50 * time.Millisecond
+		},
+		StashLimit: 2,
+		ResetAfter: time.Minute,
+	}
+
+	supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+	require.Equal(t, actorkit.STOPPED, child.Actor().State())
+	require.Equal(t, 1, supervisor.Attempts(child.Actor()))
+
+	require.NoError(t, child.Send(1, nil))
+	require.NoError(t, child.Send(2, nil))
+	require.Error(t, child.Send(3, nil))
+
+	require.Equal(t, 1, (<-base.Message).Data)
+	require.Equal(t, 2, (<-base.Message).Data)
+	require.True(t, isRunning(child))
+
+	// a failure shortly after restart increases the backoff.
+	supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+	require.Equal(t, 2, supervisor.Attempts(child.Actor()))
+
+	require.NoError(t, child.Send(4, nil))
+	require.Equal(t, 4, (<-base.Message).Data)
+
+	// a failure after a period of stability resets the backoff.
+	clock.Advance(time.Minute)
+	supervisor.Handle(errors.New("bad day"), child, child.Actor(), system.Actor())
+	require.Equal(t, 1, supervisor.Attempts(child.Actor()))
+
+	require.NoError(t, child.Send(5, nil))
+	require.Equal(t, 5, (<-base.Message).Data)
+}
+
+func TestSuspendedActorDeadLettersStashOnKill(t *testing.T) {
+	office := actorkit.NewDeadLetterOffice(10)
+	base := &basic{Message: make(chan *actorkit.Envelope, 1)}
+
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base, DeadLetters: office})
+	require.NoError(t, am.Start())
+
+	require.NoError(t, am.Suspend(0))
+	addr := actorkit.AddressOf(am, "basic")
+	require.NoError(t, addr.Send(1, nil))
+	require.Equal(t, 1, am.Stashed())
+
+	require.NoError(t, am.Kill())
+	require.Equal(t, 0, am.Stashed())
+	require.Equal(t, int64(1), office.Count(actorkit.ActorStoppedReason))
+
+	require.Error(t, addr.Send(2, nil))
+}
+
+func TestSuspendedActorRestartsWithoutTerminating(t *testing.T) {
+	base := &basic{Message: make(chan *actorkit.Envelope, 1)}
+	am := actorkit.NewActorImpl("ns", "ds", actorkit.Prop{Behaviour: base})
+	require.NoError(t, am.Start())
+	defer am.Destroy()
+
+	signals := make(chan actorkit.Signal, 10)
+	defer am.Watch(func(event interface{}) {
+		switch tm := event.(type) {
+		case actorkit.Terminated:
+			signals <- tm.Signal
+		case actorkit.ActorSignal:
+			if tm.Signal == actorkit.RESTARTED {
+				signals <- tm.Signal
+			}
+		}
+	}).Stop()
+
+	require.NoError(t, am.Suspend(0))
+	require.Equal(t, actorkit.STOPPED, am.State())
+	require.Len(t, signals, 0)
+
+	require.NoError(t, am.Restart())
+	require.True(t, isRunning(am))
+	require.Equal(t, int64(1), am.Stats().Restarted)
+	require.Equal(t, actorkit.RESTARTED, <-signals)
+	require.Len(t, signals, 0)
+
+	// a plain stop after the restart notifies watchers again.
+	require.NoError(t, am.Stop())
+	require.Equal(t, actorkit.STOPPED, <-signals)
+}
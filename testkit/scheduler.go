@@ -0,0 +1,418 @@
+package testkit
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/errors"
+)
+
+const defaultProcessTimeout = 5 * time.Second
+
+var (
+	// ErrProcessTimeout is returned when an actor did not finish processing a
+	// delivered message within the Scheduler's ProcessTimeout.
+	ErrProcessTimeout = errors.New("actor did not finish processing message within timeout")
+
+	// epoch is the starting time of all virtual clocks of Schedulers.
+	epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+var (
+	_ actorkit.Clock          = &Scheduler{}
+	_ actorkit.MailboxFactory = &Scheduler{}
+	_ actorkit.Mailbox        = &schedulerBox{}
+)
+
+//*****************************************************************************
+// Scheduler
+//*****************************************************************************
+
+// Scheduler implements a deterministic dispatcher for actors, where all actors using
+// it's mailboxes only process a message when the Scheduler delivers one to them, which
+// happens one message at a time, in a order decided by a pseudo-random source seeded
+// with a user provided seed. This allows a test to be replayed exactly with a giving
+// seed.
+//
+// Scheduler also implements the actorkit.Clock interface as a virtual clock, which
+// only moves forward when Scheduler.Advance is called, firing all timers due within
+// the new time. This allows supervisor delays, backoffs and future timeouts to be
+// tested without any real sleep.
+//
+// Use Scheduler.Prop to setup a Prop for the root actor, all descendants will inherit
+// it's clock and mailboxes.
+type Scheduler struct {
+	// ProcessTimeout sets the real time duration a Step will wait for an actor
+	// to finish processing a delivered message, before failing with ErrProcessTimeout.
+	//
+	// Defaults to 5 seconds.
+	ProcessTimeout time.Duration
+
+	ml    sync.Mutex
+	cond  *sync.Cond
+	rand  *rand.Rand
+	boxes []*schedulerBox
+
+	tl     sync.Mutex
+	now    time.Time
+	seq    int64
+	timers []*schedulerTimer
+}
+
+// NewScheduler returns a new Scheduler using provided seed for it's ordering
+// of message deliveries.
+func NewScheduler(seed int64) *Scheduler {
+	var s Scheduler
+	s.now = epoch
+	s.cond = sync.NewCond(&s.ml)
+	s.rand = rand.New(rand.NewSource(seed))
+	s.ProcessTimeout = defaultProcessTimeout
+	return &s
+}
+
+// Prop returns a copy of provided Prop using the Scheduler as it's clock and
+// mailbox factory.
+func (s *Scheduler) Prop(prop actorkit.Prop) actorkit.Prop {
+	prop.Clock = s
+	prop.Mailboxes = s
+	prop.Mailbox = nil
+	return prop
+}
+
+// NewMailbox implements the actorkit.MailboxFactory interface, returning a mailbox
+// whose messages are only delivered by the Scheduler.
+func (s *Scheduler) NewMailbox(invoker actorkit.MailInvoker) actorkit.Mailbox {
+	box := &schedulerBox{sched: s, invoker: invoker}
+
+	s.ml.Lock()
+	s.boxes = append(s.boxes, box)
+	s.ml.Unlock()
+
+	return box
+}
+
+// Pending returns the total messages pending delivery in all mailboxes of
+// Scheduler.
+func (s *Scheduler) Pending() int {
+	s.ml.Lock()
+	defer s.ml.Unlock()
+
+	var total int
+	for _, box := range s.boxes {
+		total += len(box.items)
+	}
+	return total
+}
+
+// Step delivers a single message to one of the actors awaiting messages, choosing
+// said actor using Scheduler's random source. It blocks till the actor has finished
+// processing the message, returning false if no message was delivered.
+func (s *Scheduler) Step() (bool, error) {
+	s.ml.Lock()
+	defer s.ml.Unlock()
+
+	var ready []*schedulerBox
+	for _, box := range s.boxes {
+		if box.waiting && !box.granted && len(box.items) != 0 {
+			ready = append(ready, box)
+		}
+	}
+
+	if len(ready) == 0 {
+		return false, nil
+	}
+
+	box := ready[s.rand.Intn(len(ready))]
+	box.granted = true
+	s.cond.Broadcast()
+
+	var expired bool
+	watchdog := time.AfterFunc(s.ProcessTimeout, func() {
+		s.ml.Lock()
+		expired = true
+		s.ml.Unlock()
+		s.cond.Broadcast()
+	})
+	defer watchdog.Stop()
+
+	for box.granted || box.busy {
+		if expired {
+			return true, errors.Wrap(ErrProcessTimeout, "Awaited actor for %s", s.ProcessTimeout)
+		}
+		s.cond.Wait()
+	}
+	return true, nil
+}
+
+// Run delivers messages through Scheduler.Step till no message is left to be
+// delivered, returning total messages delivered.
+func (s *Scheduler) Run() (int, error) {
+	var total int
+	for {
+		delivered, err := s.Step()
+		if err != nil {
+			return total, err
+		}
+		if !delivered {
+			return total, nil
+		}
+		total++
+	}
+}
+
+// Await runs provided function in a goroutine, delivering messages through
+// Scheduler.Step till the function returns. This is useful for operations
+// which block on the processing of pending messages, like stopping an actor.
+func (s *Scheduler) Await(fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		default:
+		}
+
+		delivered, err := s.Step()
+		if err != nil {
+			return err
+		}
+		if !delivered {
+			runtime.Gosched()
+		}
+	}
+}
+
+// Now implements the actorkit.Clock interface, returning the Scheduler's
+// virtual time.
+func (s *Scheduler) Now() time.Time {
+	s.tl.Lock()
+	defer s.tl.Unlock()
+	return s.now
+}
+
+// AfterFunc implements the actorkit.Clock interface, scheduling provided function
+// to be called once Scheduler's virtual time is advanced past provided duration.
+func (s *Scheduler) AfterFunc(dur time.Duration, fn func()) actorkit.Timer {
+	s.tl.Lock()
+	defer s.tl.Unlock()
+
+	s.seq++
+	timer := &schedulerTimer{sched: s, at: s.now.Add(dur), seq: s.seq, fn: fn}
+	s.timers = append(s.timers, timer)
+	return timer
+}
+
+// Timers returns total timers scheduled but yet to fire.
+func (s *Scheduler) Timers() int {
+	s.tl.Lock()
+	defer s.tl.Unlock()
+	return len(s.timers)
+}
+
+// Advance moves Scheduler's virtual time forward by provided duration, firing all
+// timers due within the new time in order of their due time, on the calling goroutine.
+func (s *Scheduler) Advance(dur time.Duration) {
+	s.tl.Lock()
+	target := s.now.Add(dur)
+	s.tl.Unlock()
+
+	for {
+		s.tl.Lock()
+		sort.Slice(s.timers, func(i, j int) bool {
+			if s.timers[i].at.Equal(s.timers[j].at) {
+				return s.timers[i].seq < s.timers[j].seq
+			}
+			return s.timers[i].at.Before(s.timers[j].at)
+		})
+
+		if len(s.timers) == 0 || s.timers[0].at.After(target) {
+			s.now = target
+			s.tl.Unlock()
+			return
+		}
+
+		next := s.timers[0]
+		s.timers = s.timers[1:]
+		if next.at.After(s.now) {
+			s.now = next.at
+		}
+		s.tl.Unlock()
+
+		next.fn()
+	}
+}
+
+func (s *Scheduler) stop(timer *schedulerTimer) bool {
+	s.tl.Lock()
+	defer s.tl.Unlock()
+
+	for index, item := range s.timers {
+		if item == timer {
+			s.timers = append(s.timers[:index], s.timers[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//*****************************************************************************
+// schedulerTimer
+//*****************************************************************************
+
+type schedulerTimer struct {
+	sched *Scheduler
+	at    time.Time
+	seq   int64
+	fn    func()
+}
+
+// Stop implements the actorkit.Timer interface.
+func (t *schedulerTimer) Stop() bool {
+	return t.sched.stop(t)
+}
+
+//*****************************************************************************
+// schedulerBox
+//*****************************************************************************
+
+type scheduledMail struct {
+	addr actorkit.Addr
+	env  actorkit.Envelope
+}
+
+// schedulerBox implements the actorkit.Mailbox interface, where the actor's reader
+// will only be allowed to retrieve the next message once the Scheduler grants it.
+//
+// All state of schedulerBox is guarded by it's Scheduler's lock.
+type schedulerBox struct {
+	sched   *Scheduler
+	invoker actorkit.MailInvoker
+	items   []scheduledMail
+
+	// waiting is true when the actor's reader is blocked on Wait.
+	waiting bool
+
+	// granted is true when the Scheduler allowed the delivery of head message.
+	granted bool
+
+	// busy is true when the reader retrieved a granted message and is yet to
+	// call Wait again.
+	busy bool
+
+	// signalled is true when Signal was called to wake up the reader.
+	signalled bool
+
+	// woken is true when reader was woken by a Signal without a grant.
+	woken bool
+}
+
+// Wait blocks till the Scheduler grants a delivery or the box is signalled.
+func (b *schedulerBox) Wait() {
+	b.sched.ml.Lock()
+	defer b.sched.ml.Unlock()
+
+	b.busy = false
+	b.waiting = true
+	b.sched.cond.Broadcast()
+
+	for !b.granted && !b.signalled {
+		b.sched.cond.Wait()
+	}
+
+	b.waiting = false
+
+	// a signal takes precedence as the reader is probably being stopped, hence
+	// revoke any grant, leaving the message in the mailbox.
+	if b.signalled {
+		b.signalled = false
+		b.granted = false
+		b.woken = true
+		b.sched.cond.Broadcast()
+	}
+}
+
+func (b *schedulerBox) Signal() {
+	b.sched.ml.Lock()
+	b.signalled = true
+	b.sched.ml.Unlock()
+	b.sched.cond.Broadcast()
+}
+
+func (b *schedulerBox) Clear() {
+	b.sched.ml.Lock()
+	b.items = nil
+	b.granted = false
+	b.sched.ml.Unlock()
+	b.sched.cond.Broadcast()
+}
+
+func (b *schedulerBox) Cap() int {
+	return -1
+}
+
+func (b *schedulerBox) Total() int {
+	b.sched.ml.Lock()
+	defer b.sched.ml.Unlock()
+	return len(b.items)
+}
+
+func (b *schedulerBox) IsEmpty() bool {
+	return b.Total() == 0
+}
+
+func (b *schedulerBox) Unpop(addr actorkit.Addr, env actorkit.Envelope) {
+	b.sched.ml.Lock()
+	b.items = append([]scheduledMail{{addr: addr, env: env}}, b.items...)
+	b.sched.ml.Unlock()
+}
+
+func (b *schedulerBox) Push(addr actorkit.Addr, env actorkit.Envelope) error {
+	b.sched.ml.Lock()
+	b.items = append(b.items, scheduledMail{addr: addr, env: env})
+	b.sched.ml.Unlock()
+
+	if b.invoker != nil {
+		b.invoker.InvokedReceived(addr, env)
+	}
+	return nil
+}
+
+// Pop returns the granted message if any. If the reader was woken without a grant,
+// an error is returned, else the head message is returned, which allows the
+// exhaustion of a stopped actor's mailbox.
+func (b *schedulerBox) Pop() (actorkit.Addr, actorkit.Envelope, error) {
+	b.sched.ml.Lock()
+
+	if b.granted {
+		b.granted = false
+		b.busy = true
+	} else if b.woken {
+		b.woken = false
+		b.sched.ml.Unlock()
+		return nil, actorkit.Envelope{}, errors.Wrap(actorkit.ErrMailboxEmpty, "no delivery granted")
+	}
+
+	if len(b.items) == 0 {
+		b.busy = false
+		b.sched.ml.Unlock()
+		b.sched.cond.Broadcast()
+		return nil, actorkit.Envelope{}, errors.Wrap(actorkit.ErrMailboxEmpty, "empty mailbox")
+	}
+
+	next := b.items[0]
+	b.items = b.items[1:]
+	b.sched.ml.Unlock()
+
+	if b.invoker != nil {
+		b.invoker.InvokedDispatched(next.addr, next.env)
+	}
+	return next.addr, next.env, nil
+}
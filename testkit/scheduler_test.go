@@ -0,0 +1,142 @@
+package testkit_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/testkit"
+)
+
+type recorder struct {
+	ml    sync.Mutex
+	order []string
+}
+
+func (r *recorder) behaviour(name string) actorkit.Behaviour {
+	return actorkit.FromBehaviourFunc(func(_ actorkit.Addr, env actorkit.Envelope) {
+		r.ml.Lock()
+		r.order = append(r.order, fmt.Sprintf("%s:%v", name, env.Data))
+		r.ml.Unlock()
+	})
+}
+
+func runScheduled(t *testing.T, seed int64) []string {
+	sch := testkit.NewScheduler(seed)
+	system, err := testkit.NewSystem(sch.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	var rec recorder
+	var addrs []actorkit.Addr
+	for _, name := range []string{"a", "b", "c"} {
+		addr, err := system.Spawn(name, actorkit.Prop{Behaviour: rec.behaviour(name)})
+		require.NoError(t, err)
+		addrs = append(addrs, addr)
+	}
+
+	for i := 0; i < 5; i++ {
+		for _, addr := range addrs {
+			require.NoError(t, addr.Send(i, system.Root()))
+		}
+	}
+
+	require.Equal(t, 15, sch.Pending())
+	delivered, err := sch.Run()
+	require.NoError(t, err)
+	require.Equal(t, 15, delivered)
+	require.Equal(t, 0, sch.Pending())
+
+	return rec.order
+}
+
+func TestSchedulerIsDeterministic(t *testing.T) {
+	first := runScheduled(t, 42)
+	require.Len(t, first, 15)
+	require.Equal(t, first, runScheduled(t, 42))
+}
+
+func TestSchedulerStepDeliversOneMessage(t *testing.T) {
+	sch := testkit.NewScheduler(1)
+	system, err := testkit.NewSystem(sch.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	var rec recorder
+	addr, err := system.Spawn("a", actorkit.Prop{Behaviour: rec.behaviour("a")})
+	require.NoError(t, err)
+
+	require.NoError(t, addr.Send(1, system.Root()))
+	require.NoError(t, addr.Send(2, system.Root()))
+
+	delivered, err := sch.Step()
+	require.NoError(t, err)
+	require.True(t, delivered)
+	require.Equal(t, []string{"a:1"}, rec.order)
+
+	delivered, err = sch.Step()
+	require.NoError(t, err)
+	require.True(t, delivered)
+	require.Equal(t, []string{"a:1", "a:2"}, rec.order)
+
+	delivered, err = sch.Step()
+	require.NoError(t, err)
+	require.False(t, delivered)
+}
+
+func TestSchedulerVirtualClockTimesOutFutures(t *testing.T) {
+	sch := testkit.NewScheduler(1)
+	system, err := testkit.NewSystem(sch.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	start := sch.Now()
+	future := system.Root().TimedFuture(time.Minute)
+	require.Equal(t, 1, sch.Timers())
+
+	sch.Advance(59 * time.Second)
+	require.NoError(t, future.Err())
+	require.Equal(t, start.Add(59*time.Second), sch.Now())
+
+	sch.Advance(time.Second)
+	require.Error(t, future.Err())
+	require.Equal(t, 0, sch.Timers())
+}
+
+func TestSchedulerResolvedFutureStopsTimer(t *testing.T) {
+	sch := testkit.NewScheduler(1)
+	system, err := testkit.NewSystem(sch.Prop(actorkit.Prop{}))
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	future := system.Root().TimedFuture(time.Minute)
+	require.NoError(t, future.Send("done", system.Root()))
+	require.Equal(t, 0, sch.Timers())
+
+	sch.Advance(time.Hour)
+	require.NoError(t, future.Err())
+	require.Equal(t, "done", future.Result().Data)
+}
+
+func TestSchedulerTimersFireInOrder(t *testing.T) {
+	sch := testkit.NewScheduler(1)
+
+	var fired []int
+	sch.AfterFunc(3*time.Second, func() { fired = append(fired, 3) })
+	sch.AfterFunc(time.Second, func() {
+		fired = append(fired, 1)
+		sch.AfterFunc(time.Second, func() { fired = append(fired, 2) })
+	})
+	stopped := sch.AfterFunc(2*time.Second, func() { fired = append(fired, -1) })
+	require.True(t, stopped.Stop())
+
+	sch.Advance(2 * time.Second)
+	require.Equal(t, []int{1, 2}, fired)
+
+	sch.Advance(time.Second)
+	require.Equal(t, []int{1, 2, 3}, fired)
+}
@@ -0,0 +1,300 @@
+// Package testkit provides helpers for testing actors, it provides TestProbes, which are
+// actor addresses that record all envelopes they receive, with expectation methods which
+// await such messages within giving timeouts, instead of relying on sleeps and counters.
+package testkit
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/errors"
+)
+
+const (
+	testProtocol  = "testkit"
+	testNamespace = "localhost"
+)
+
+var (
+	// ErrNoMessage is returned when no message was received within a giving timeout.
+	ErrNoMessage = errors.New("no message received within timeout")
+
+	// ErrUnexpectedMessage is returned when a message was received when none or a
+	// different one was expected.
+	ErrUnexpectedMessage = errors.New("received unexpected message")
+
+	// ErrNotTerminated is returned when a watched address did not terminate
+	// within a giving timeout.
+	ErrNotTerminated = errors.New("address did not terminate within timeout")
+)
+
+//*****************************************************************************
+// System
+//*****************************************************************************
+
+// System embodies a throwaway root actor created through actorkit.Ancestor, which
+// can be used to spawn actors and probes under test and torn down once done.
+type System struct {
+	root actorkit.Addr
+}
+
+// NewSystem returns a new System with a started root actor using provided Prop.
+func NewSystem(prop actorkit.Prop) (*System, error) {
+	root, err := actorkit.Ancestor(testProtocol, testNamespace, prop)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create root actor")
+	}
+	return &System{root: root}, nil
+}
+
+// Root returns the address of the root actor of the system.
+func (s *System) Root() actorkit.Addr {
+	return s.root
+}
+
+// Spawn spawns a new actor for giving service under the system's root actor.
+func (s *System) Spawn(service string, prop actorkit.Prop) (actorkit.Addr, error) {
+	return s.root.Spawn(service, prop)
+}
+
+// NewProbe returns a new TestProbe spawned under the system's root actor.
+func (s *System) NewProbe(service string) (*TestProbe, error) {
+	return NewProbe(s.root, service)
+}
+
+// Shutdown destroys the root actor and all it's descendants.
+func (s *System) Shutdown() error {
+	return actorkit.Destroy(s.root)
+}
+
+//*****************************************************************************
+// TestProbe
+//*****************************************************************************
+
+// TestProbe implements the actorkit.Behaviour interface, recording all envelopes
+// delivered to it's actor, which can be awaited through it's Expect methods.
+//
+// TestProbe addresses are useful as the sender of envelopes sent to the actors
+// under test, as all replies will then be recorded by the probe.
+type TestProbe struct {
+	addr   actorkit.Addr
+	notify chan struct{}
+
+	ml       sync.Mutex
+	pending  []actorkit.Envelope
+	received []actorkit.Envelope
+
+	tl     sync.Mutex
+	tn     chan struct{}
+	subs   map[string]actorkit.Subscription
+	deaths map[string]actorkit.ActorSignal
+}
+
+// NewProbe returns a new TestProbe spawned as a child of provided parent.
+func NewProbe(parent actorkit.Spawner, service string) (*TestProbe, error) {
+	probe := &TestProbe{
+		notify: make(chan struct{}, 1),
+		tn:     make(chan struct{}, 1),
+		subs:   map[string]actorkit.Subscription{},
+		deaths: map[string]actorkit.ActorSignal{},
+	}
+
+	addr, err := parent.Spawn(service, actorkit.Prop{Behaviour: probe})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to spawn probe")
+	}
+
+	probe.addr = addr
+	return probe, nil
+}
+
+// Action implements the actorkit.Behaviour interface.
+func (p *TestProbe) Action(_ actorkit.Addr, env actorkit.Envelope) {
+	p.ml.Lock()
+	p.pending = append(p.pending, env)
+	p.received = append(p.received, env)
+	p.ml.Unlock()
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Addr returns the address of the probe's actor.
+func (p *TestProbe) Addr() actorkit.Addr {
+	return p.addr
+}
+
+// Received returns all envelopes received by probe, including those
+// already consumed by the Expect methods.
+func (p *TestProbe) Received() []actorkit.Envelope {
+	p.ml.Lock()
+	defer p.ml.Unlock()
+
+	envs := make([]actorkit.Envelope, len(p.received))
+	copy(envs, p.received)
+	return envs
+}
+
+// Send delivers giving data to target address with probe's address as sender,
+// ensuring all replies are recorded by the probe.
+func (p *TestProbe) Send(target actorkit.Addr, data interface{}) error {
+	return target.Send(data, p.addr)
+}
+
+// ExpectMessage returns the next envelope received by probe, else returning an
+// error if none arrived within timeout.
+func (p *TestProbe) ExpectMessage(timeout time.Duration) (actorkit.Envelope, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if env, ok := p.next(); ok {
+			return env, nil
+		}
+
+		select {
+		case <-p.notify:
+		case <-timer.C:
+			if env, ok := p.next(); ok {
+				return env, nil
+			}
+			return actorkit.Envelope{}, errors.Wrap(ErrNoMessage, "Probe %q awaited %s", p.addr.Addr(), timeout)
+		}
+	}
+}
+
+// ExpectMessageData returns the next envelope received by probe, returning an error
+// if none arrived within timeout or if it's data is not deeply equal to provided data.
+func (p *TestProbe) ExpectMessageData(data interface{}, timeout time.Duration) (actorkit.Envelope, error) {
+	env, err := p.ExpectMessage(timeout)
+	if err != nil {
+		return env, err
+	}
+
+	if !reflect.DeepEqual(data, env.Data) {
+		return env, errors.Wrap(ErrUnexpectedMessage, "Expected data %#v but got %#v", data, env.Data)
+	}
+	return env, nil
+}
+
+// ExpectMessageOfType returns the next envelope received by probe, returning an error
+// if none arrived within timeout or if it's data is not of the same type as sample.
+func (p *TestProbe) ExpectMessageOfType(sample interface{}, timeout time.Duration) (actorkit.Envelope, error) {
+	env, err := p.ExpectMessage(timeout)
+	if err != nil {
+		return env, err
+	}
+
+	if reflect.TypeOf(sample) != reflect.TypeOf(env.Data) {
+		return env, errors.Wrap(ErrUnexpectedMessage, "Expected type %T but got %T", sample, env.Data)
+	}
+	return env, nil
+}
+
+// ExpectNoMessageWithin returns an error if any envelope is received by probe
+// within provided duration.
+func (p *TestProbe) ExpectNoMessageWithin(dur time.Duration) error {
+	env, err := p.ExpectMessage(dur)
+	if err != nil {
+		return nil
+	}
+	return errors.Wrap(ErrUnexpectedMessage, "Expected no message but got %s", describe(env))
+}
+
+// Watch registers target address for termination watch, where any Terminated event
+// published by target will be recorded for ExpectTerminated. Being stopped as part of
+// a restart is not a termination.
+func (p *TestProbe) Watch(target actorkit.Addr) {
+	p.tl.Lock()
+	defer p.tl.Unlock()
+
+	if _, ok := p.subs[target.Addr()]; ok {
+		return
+	}
+
+	key := target.Addr()
+	p.subs[key] = target.Watch(func(event interface{}) {
+		terminated, ok := event.(actorkit.Terminated)
+		if !ok {
+			return
+		}
+
+		p.tl.Lock()
+		p.deaths[key] = actorkit.ActorSignal{
+			Addr:    terminated.Addr,
+			Signal:  terminated.Signal,
+			Payload: terminated.Cause,
+		}
+		p.tl.Unlock()
+
+		select {
+		case p.tn <- struct{}{}:
+		default:
+		}
+	})
+}
+
+// Unwatch removes target address from probe's termination watch.
+func (p *TestProbe) Unwatch(target actorkit.Addr) {
+	p.tl.Lock()
+	defer p.tl.Unlock()
+
+	if sub, ok := p.subs[target.Addr()]; ok {
+		delete(p.subs, target.Addr())
+		delete(p.deaths, target.Addr())
+		sub.Stop()
+	}
+}
+
+// ExpectTerminated returns the termination signal of a target address previously
+// registered through TestProbe.Watch, with the cause of termination if any as it's
+// payload, returning an error if target did not terminate within timeout.
+func (p *TestProbe) ExpectTerminated(target actorkit.Addr, timeout time.Duration) (actorkit.ActorSignal, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if signal, ok := p.death(target.Addr()); ok {
+			return signal, nil
+		}
+
+		select {
+		case <-p.tn:
+		case <-timer.C:
+			if signal, ok := p.death(target.Addr()); ok {
+				return signal, nil
+			}
+			return actorkit.ActorSignal{}, errors.Wrap(ErrNotTerminated, "Probe %q awaited %q for %s", p.addr.Addr(), target.Addr(), timeout)
+		}
+	}
+}
+
+func (p *TestProbe) death(key string) (actorkit.ActorSignal, bool) {
+	p.tl.Lock()
+	defer p.tl.Unlock()
+
+	signal, ok := p.deaths[key]
+	return signal, ok
+}
+
+func (p *TestProbe) next() (actorkit.Envelope, bool) {
+	p.ml.Lock()
+	defer p.ml.Unlock()
+
+	if len(p.pending) == 0 {
+		return actorkit.Envelope{}, false
+	}
+
+	env := p.pending[0]
+	p.pending = p.pending[1:]
+	return env, true
+}
+
+func describe(env actorkit.Envelope) string {
+	return fmt.Sprintf("envelope %q with data %#v", env.Ref.String(), env.Data)
+}
@@ -0,0 +1,98 @@
+package testkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gokit/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/testkit"
+)
+
+type ping struct{}
+type pong struct{ Count int }
+
+func echo() actorkit.Behaviour {
+	var count int
+	return actorkit.FromBehaviourFunc(func(me actorkit.Addr, env actorkit.Envelope) {
+		if _, ok := env.Data.(ping); ok {
+			count++
+			env.Sender.Send(pong{Count: count}, me)
+		}
+	})
+}
+
+func TestProbeExpectations(t *testing.T) {
+	system, err := testkit.NewSystem(actorkit.Prop{})
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	echoAddr, err := system.Spawn("echo", actorkit.Prop{Behaviour: echo()})
+	require.NoError(t, err)
+
+	probe, err := system.NewProbe("probe")
+	require.NoError(t, err)
+
+	require.NoError(t, probe.Send(echoAddr, ping{}))
+	_, err = probe.ExpectMessageData(pong{Count: 1}, time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, probe.Send(echoAddr, ping{}))
+	env, err := probe.ExpectMessageOfType(pong{}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, echoAddr.ID(), env.Sender.ID())
+
+	require.NoError(t, probe.ExpectNoMessageWithin(100*time.Millisecond))
+	require.Len(t, probe.Received(), 2)
+
+	require.NoError(t, probe.Send(echoAddr, ping{}))
+	_, err = probe.ExpectMessageOfType(ping{}, time.Second)
+	require.Error(t, err)
+
+	_, err = probe.ExpectMessage(50 * time.Millisecond)
+	require.Error(t, err)
+}
+
+func TestProbeExpectTerminated(t *testing.T) {
+	system, err := testkit.NewSystem(actorkit.Prop{})
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	echoAddr, err := system.Spawn("echo", actorkit.Prop{Behaviour: echo()})
+	require.NoError(t, err)
+
+	probe, err := system.NewProbe("probe")
+	require.NoError(t, err)
+
+	probe.Watch(echoAddr)
+
+	_, err = probe.ExpectTerminated(echoAddr, 50*time.Millisecond)
+	require.Error(t, err)
+
+	require.NoError(t, actorkit.Kill(echoAddr))
+
+	signal, err := probe.ExpectTerminated(echoAddr, time.Second)
+	require.NoError(t, err)
+	require.Contains(t, []actorkit.Signal{actorkit.STOPPED, actorkit.KILLED}, signal.Signal)
+}
+
+func TestProbeIgnoresRestarts(t *testing.T) {
+	system, err := testkit.NewSystem(actorkit.Prop{})
+	require.NoError(t, err)
+	defer system.Shutdown()
+
+	echoAddr, err := system.Spawn("echo", actorkit.Prop{Behaviour: echo()})
+	require.NoError(t, err)
+
+	probe, err := system.NewProbe("probe")
+	require.NoError(t, err)
+
+	probe.Watch(echoAddr)
+	require.NoError(t, actorkit.Restart(echoAddr))
+
+	_, err = probe.ExpectTerminated(echoAddr, 50*time.Millisecond)
+	require.Error(t, err)
+	require.True(t, errors.IsAny(err, testkit.ErrNotTerminated))
+}
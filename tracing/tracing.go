@@ -0,0 +1,259 @@
+// Package tracing provides distributed tracing for actorkit using opencensus, where spans
+// are started and ended for each processed message through a actorkit.MessageInvoker, and
+// span contexts are carried within an envelope's context or it's header, allowing a trace
+// to continue across actors and pubsub hops.
+package tracing
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/pubsubs"
+)
+
+const (
+	// SpanContextHeader defines the header key used to carry a binary encoded
+	// span context in base64 format within an envelope's header.
+	SpanContextHeader = "X-Actorkit-Trace"
+
+	spanPrefix = "actorkit"
+)
+
+var (
+	_ actorkit.MessageInvoker  = &Invoker{}
+	_ actorkit.MessageInjector = &Invoker{}
+	_ pubsubs.Marshaler        = Marshaler{}
+)
+
+//*****************************************************************************
+// Propagation
+//*****************************************************************************
+
+// Inject returns a copy of provided envelope with the span context of the span in
+// provided context set into a copy of it's header. The envelope is returned as is if
+// context has no span.
+func Inject(ctx context.Context, env actorkit.Envelope) actorkit.Envelope {
+	span := trace.FromContext(ctx)
+	if span == nil {
+		return env
+	}
+	return InjectSpanContext(span.SpanContext(), env)
+}
+
+// InjectSpanContext returns a copy of provided envelope with provided span context
+// set into a copy of it's header.
+func InjectSpanContext(sc trace.SpanContext, env actorkit.Envelope) actorkit.Envelope {
+	header := make(actorkit.Header, len(env.Header)+1)
+	for k, v := range env.Header {
+		header[k] = v
+	}
+
+	header[SpanContextHeader] = base64.StdEncoding.EncodeToString(propagation.Binary(sc))
+	env.Header = header
+	return env
+}
+
+// Extract returns the span context carried within provided envelope's header, if
+// any and valid.
+func Extract(env actorkit.Envelope) (trace.SpanContext, bool) {
+	value, ok := env.Header[SpanContextHeader]
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	return propagation.FromBinary(decoded)
+}
+
+// SendContext delivers provided data to giving address with provided context attached
+// to it's envelope, where the span context of the context's span if any is also set into
+// the envelope's header.
+func SendContext(ctx context.Context, addr actorkit.Addr, data interface{}, sender actorkit.Addr) error {
+	env := actorkit.CreateEnvelope(sender, actorkit.Header{}, data).WithContext(ctx)
+	return addr.Forward(Inject(ctx, env))
+}
+
+//*****************************************************************************
+// Invoker
+//*****************************************************************************
+
+// Invoker implements the actorkit.MessageInvoker interface, starting a span when an
+// actor starts processing an envelope and ending it once processed. The parent of a
+// span is the span within the envelope's context, else the span context within the
+// envelope's header, else a new trace is started.
+//
+// Invoker also implements the actorkit.MessageInjector interface, where envelopes sent
+// to an actor are injected with the span context of the span within their context, else
+// of the span of the envelope being processed by their sender's actor. Hence envelopes
+// sent by a behaviour with it's own address as sender continue the trace without the
+// use of SendContext, as long as both actors use the same Invoker.
+//
+// Invoker can be used as the Prop.MessageInvoker of an actor, which is then inherited
+// by all it's children.
+type Invoker struct {
+	// Next sets a MessageInvoker which will also be called by Invoker.
+	Next actorkit.MessageInvoker
+
+	// Options sets the options used to start all spans.
+	Options []trace.StartOption
+
+	ml     sync.Mutex
+	spans  map[string]*trace.Span
+	ctxs   map[string]context.Context
+	active map[string]*trace.Span
+}
+
+// NewInvoker returns a new Invoker using provided options for starting spans.
+func NewInvoker(ops ...trace.StartOption) *Invoker {
+	return &Invoker{Options: ops}
+}
+
+// InjectRequest implements the actorkit.MessageInjector interface, setting the span
+// context of the sending span into the header of provided envelope if it has none.
+func (i *Invoker) InjectRequest(addr actorkit.Addr, env actorkit.Envelope) actorkit.Envelope {
+	if injector, ok := i.Next.(actorkit.MessageInjector); ok {
+		env = injector.InjectRequest(addr, env)
+	}
+
+	if env.Has(SpanContextHeader) {
+		return env
+	}
+
+	if trace.FromContext(env.Context()) != nil {
+		return Inject(env.Context(), env)
+	}
+
+	if env.Sender == nil {
+		return env
+	}
+
+	i.ml.Lock()
+	span, ok := i.active[env.Sender.ID()]
+	i.ml.Unlock()
+
+	if !ok {
+		return env
+	}
+	return InjectSpanContext(span.SpanContext(), env)
+}
+
+// InvokedRequest implements the actorkit.MessageInvoker interface.
+func (i *Invoker) InvokedRequest(addr actorkit.Addr, env actorkit.Envelope) {
+	if i.Next != nil {
+		i.Next.InvokedRequest(addr, env)
+	}
+}
+
+// InvokedProcessing implements the actorkit.MessageInvoker interface, starting
+// a span for the processing of provided envelope.
+func (i *Invoker) InvokedProcessing(addr actorkit.Addr, env actorkit.Envelope) {
+	name := fmt.Sprintf("%s/%s", spanPrefix, addr.Addr())
+	ops := append([]trace.StartOption{trace.WithSpanKind(trace.SpanKindServer)}, i.Options...)
+
+	var ctx context.Context
+	var span *trace.Span
+
+	if trace.FromContext(env.Context()) != nil {
+		ctx, span = trace.StartSpan(env.Context(), name, ops...)
+	} else if sc, ok := Extract(env); ok {
+		ctx, span = trace.StartSpanWithRemoteParent(env.Context(), name, sc, ops...)
+	} else {
+		ctx, span = trace.StartSpan(env.Context(), name, ops...)
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("actorkit.addr", addr.Addr()),
+		trace.StringAttribute("actorkit.ref", env.Ref.String()),
+		trace.StringAttribute("actorkit.type", fmt.Sprintf("%T", env.Data)),
+	)
+
+	key := spanKey(addr, env)
+
+	i.ml.Lock()
+	if i.spans == nil {
+		i.spans = map[string]*trace.Span{}
+		i.ctxs = map[string]context.Context{}
+		i.active = map[string]*trace.Span{}
+	}
+	i.spans[key] = span
+	i.ctxs[key] = ctx
+	i.active[addr.ID()] = span
+	i.ml.Unlock()
+
+	if i.Next != nil {
+		i.Next.InvokedProcessing(addr, env)
+	}
+}
+
+// InvokedProcessed implements the actorkit.MessageInvoker interface, ending the
+// span started for the processing of provided envelope. It is also called when
+// the actor's behaviour panics while processing envelope.
+func (i *Invoker) InvokedProcessed(addr actorkit.Addr, env actorkit.Envelope) {
+	key := spanKey(addr, env)
+
+	i.ml.Lock()
+	span, ok := i.spans[key]
+	delete(i.spans, key)
+	delete(i.ctxs, key)
+	if ok && i.active[addr.ID()] == span {
+		delete(i.active, addr.ID())
+	}
+	i.ml.Unlock()
+
+	if ok {
+		span.End()
+	}
+
+	if i.Next != nil {
+		i.Next.InvokedProcessed(addr, env)
+	}
+}
+
+// ContextOf returns a context holding the span of the envelope being processed by
+// the actor of giving address, which should be used by a behaviour as the context of
+// outgoing envelopes to continue it's trace. The envelope's context is returned if
+// envelope is not being processed.
+func (i *Invoker) ContextOf(addr actorkit.Addr, env actorkit.Envelope) context.Context {
+	i.ml.Lock()
+	defer i.ml.Unlock()
+
+	if ctx, ok := i.ctxs[spanKey(addr, env)]; ok {
+		return ctx
+	}
+	return env.Context()
+}
+
+func spanKey(addr actorkit.Addr, env actorkit.Envelope) string {
+	return addr.Addr() + "#" + env.Ref.String()
+}
+
+//*****************************************************************************
+// Marshaler
+//*****************************************************************************
+
+// Marshaler implements the pubsubs.Marshaler interface, setting the span context
+// of the span within an envelope's context into it's header before marshaling it
+// with the wrapped Marshaler, ensuring traces continue across pubsub hops.
+//
+// The receiving side requires no special Unmarshaler, as long as the header is
+// retained, as the Invoker extracts the span context from the header.
+type Marshaler struct {
+	Next pubsubs.Marshaler
+}
+
+// Marshal implements the pubsubs.Marshaler interface.
+func (m Marshaler) Marshal(env actorkit.Envelope) ([]byte, error) {
+	if !env.Has(SpanContextHeader) {
+		env = Inject(env.Context(), env)
+	}
+	return m.Next.Marshal(env)
+}
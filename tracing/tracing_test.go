@@ -0,0 +1,203 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+
+	"github.com/gokit/actorkit"
+	"github.com/gokit/actorkit/tracing"
+)
+
+type exporter struct {
+	ml    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (e *exporter) ExportSpan(s *trace.SpanData) {
+	e.ml.Lock()
+	e.spans = append(e.spans, s)
+	e.ml.Unlock()
+}
+
+func (e *exporter) Spans() []*trace.SpanData {
+	e.ml.Lock()
+	defer e.ml.Unlock()
+	return append([]*trace.SpanData(nil), e.spans...)
+}
+
+type headerMarshaler struct{}
+
+func (headerMarshaler) Marshal(env actorkit.Envelope) ([]byte, error) {
+	return json.Marshal(env.Header)
+}
+
+type recorder struct {
+	received chan actorkit.Envelope
+}
+
+func (r *recorder) Action(_ actorkit.Addr, env actorkit.Envelope) {
+	r.received <- env
+}
+
+func TestInjectAndExtract(t *testing.T) {
+	ctx, span := trace.StartSpan(context.Background(), "root", trace.WithSampler(trace.AlwaysSample()))
+	defer span.End()
+
+	env := actorkit.CreateEnvelope(nil, actorkit.Header{}, 1)
+	require.Equal(t, env, tracing.Inject(context.Background(), env))
+
+	injected := tracing.Inject(ctx, env)
+	require.False(t, env.Has(tracing.SpanContextHeader))
+	require.True(t, injected.Has(tracing.SpanContextHeader))
+
+	sc, ok := tracing.Extract(injected)
+	require.True(t, ok)
+	require.Equal(t, span.SpanContext(), sc)
+
+	_, ok = tracing.Extract(env)
+	require.False(t, ok)
+}
+
+func TestMarshalerInjectsSpanContext(t *testing.T) {
+	ctx, span := trace.StartSpan(context.Background(), "root", trace.WithSampler(trace.AlwaysSample()))
+	defer span.End()
+
+	env := actorkit.CreateEnvelope(nil, actorkit.Header{}, 1).WithContext(ctx)
+	data, err := tracing.Marshaler{Next: headerMarshaler{}}.Marshal(env)
+	require.NoError(t, err)
+
+	var header actorkit.Header
+	require.NoError(t, json.Unmarshal(data, &header))
+
+	sc, ok := tracing.Extract(actorkit.Envelope{Header: header})
+	require.True(t, ok)
+	require.Equal(t, span.SpanContext().TraceID, sc.TraceID)
+}
+
+func TestInvokerContinuesTrace(t *testing.T) {
+	exp := &exporter{}
+	trace.RegisterExporter(exp)
+	defer trace.UnregisterExporter(exp)
+
+	invoker := tracing.NewInvoker(trace.WithSampler(trace.AlwaysSample()))
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{MessageInvoker: invoker})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	rec := &recorder{received: make(chan actorkit.Envelope, 2)}
+	target, err := system.Spawn("target", actorkit.Prop{Behaviour: rec})
+	require.NoError(t, err)
+
+	ctx, root := trace.StartSpan(context.Background(), "root", trace.WithSampler(trace.AlwaysSample()))
+	require.NoError(t, tracing.SendContext(ctx, target, 1, nil))
+	root.End()
+
+	// a remote hop only carries the span context within the header.
+	remote := tracing.InjectSpanContext(root.SpanContext(), actorkit.CreateEnvelope(nil, actorkit.Header{}, 2))
+	require.NoError(t, target.Forward(remote))
+
+	<-rec.received
+	<-rec.received
+
+	var spans []*trace.SpanData
+	for i := 0; i < 100; i++ {
+		if spans = exp.Spans(); len(spans) == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Len(t, spans, 3)
+	for _, span := range spans {
+		require.Equal(t, root.SpanContext().TraceID, span.TraceID)
+		if span.Name != "root" {
+			require.Equal(t, root.SpanContext().SpanID, span.ParentSpanID)
+			require.Equal(t, target.Addr(), span.Attributes["actorkit.addr"])
+		}
+	}
+}
+
+func waitSpans(exp *exporter, total int) []*trace.SpanData {
+	var spans []*trace.SpanData
+	for i := 0; i < 100; i++ {
+		if spans = exp.Spans(); len(spans) >= total {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return spans
+}
+
+func TestInvokerInjectsSenderSpan(t *testing.T) {
+	exp := &exporter{}
+	trace.RegisterExporter(exp)
+	defer trace.UnregisterExporter(exp)
+
+	invoker := tracing.NewInvoker(trace.WithSampler(trace.AlwaysSample()))
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{MessageInvoker: invoker})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	rec := &recorder{received: make(chan actorkit.Envelope, 1)}
+	target, err := system.Spawn("target", actorkit.Prop{Behaviour: rec})
+	require.NoError(t, err)
+
+	relay, err := system.Spawn("relay", actorkit.Prop{
+		Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+			target.Send(env.Data, addr)
+		}),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, relay.Send(1, nil))
+	received := <-rec.received
+	require.True(t, received.Has(tracing.SpanContextHeader))
+
+	spans := waitSpans(exp, 2)
+	require.Len(t, spans, 2)
+
+	byAddr := map[interface{}]*trace.SpanData{}
+	for _, span := range spans {
+		byAddr[span.Attributes["actorkit.addr"]] = span
+	}
+
+	relaySpan, targetSpan := byAddr[relay.Addr()], byAddr[target.Addr()]
+	require.NotNil(t, relaySpan)
+	require.NotNil(t, targetSpan)
+	require.Equal(t, relaySpan.TraceID, targetSpan.TraceID)
+	require.Equal(t, relaySpan.SpanID, targetSpan.ParentSpanID)
+}
+
+func TestInvokerEndsSpanOnPanic(t *testing.T) {
+	exp := &exporter{}
+	trace.RegisterExporter(exp)
+	defer trace.UnregisterExporter(exp)
+
+	invoker := tracing.NewInvoker(trace.WithSampler(trace.AlwaysSample()))
+	system, err := actorkit.Ancestor("kit", "localhost", actorkit.Prop{MessageInvoker: invoker})
+	require.NoError(t, err)
+	defer actorkit.Destroy(system)
+
+	panicky, err := system.Spawn("panicky", actorkit.Prop{
+		Supervisor: &actorkit.OneForOneSupervisor{
+			Decider: func(interface{}) actorkit.Directive {
+				return actorkit.IgnoreDirective
+			},
+		},
+		Behaviour: actorkit.FromBehaviourFunc(func(addr actorkit.Addr, env actorkit.Envelope) {
+			panic("failed")
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, panicky.Send(1, nil))
+
+	spans := waitSpans(exp, 1)
+	require.Len(t, spans, 1)
+	require.Equal(t, panicky.Addr(), spans[0].Attributes["actorkit.addr"])
+}